@@ -0,0 +1,19 @@
+{{- define "name" -}}
+gardener-extension-admission-suse-chost
+{{- end -}}
+
+{{- define "labels.app.key" -}}
+app.kubernetes.io/name
+{{- end -}}
+{{- define "labels.app.value" -}}
+{{ include "name" . }}
+{{- end -}}
+
+{{- define "labels" -}}
+{{ include "labels.app.key" . }}: {{ include "labels.app.value" . }}
+app.kubernetes.io/instance: {{ .Release.Name }}
+{{- end -}}
+
+{{- define "leaderelectionid" -}}
+gardener-extension-admission-suse-chost
+{{- end -}}
@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package chart embeds the charts of the admission component.
+package chart
+
+import (
+	"embed"
+)
+
+var (
+	// Charts contains the charts of the admission component.
+	//go:embed all:charts
+	Charts embed.FS
+	// RuntimeChartPath is the path to the chart deploying the admission component in the runtime cluster.
+	RuntimeChartPath = "charts/runtime"
+	// ApplicationChartPath is the path to the chart granting the admission component access to the virtual garden.
+	ApplicationChartPath = "charts/application"
+)
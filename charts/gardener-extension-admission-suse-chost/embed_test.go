@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package chart_test
+
+import (
+	"github.com/gardener/gardener/pkg/chartrenderer"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	. "github.com/gardener/gardener-extension-os-suse-chost/charts/gardener-extension-admission-suse-chost"
+)
+
+var _ = Describe("Runtime chart", func() {
+	const namespace = "garden"
+
+	var renderer chartrenderer.Interface
+
+	BeforeEach(func() {
+		renderer = chartrenderer.NewWithServerVersion(&version.Info{GitVersion: "v1.33.0"})
+	})
+
+	renderDeployment := func(values map[string]any) *appsv1.Deployment {
+		chart, err := renderer.RenderEmbeddedFS(Charts, RuntimeChartPath, "gardener-extension-admission-suse-chost", namespace, values)
+		Expect(err).NotTo(HaveOccurred())
+
+		deployment := &appsv1.Deployment{}
+		Expect(yaml.Unmarshal([]byte(chart.FileContent("deployment.yaml")), deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+		return deployment
+	}
+
+	It("should not mount a garden kubeconfig by default", func() {
+		deployment := renderDeployment(map[string]any{})
+
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(container.Env).NotTo(ContainElement(HaveField("Name", "GARDEN_KUBECONFIG")))
+		Expect(container.VolumeMounts).To(BeEmpty())
+		Expect(deployment.Spec.Template.Spec.Volumes).To(BeEmpty())
+	})
+
+	It("should mount the projected garden kubeconfig and pass it via GARDEN_KUBECONFIG", func() {
+		deployment := renderDeployment(map[string]any{
+			"projectedKubeconfig": map[string]any{
+				"baseMountPath":               "/var/run/secrets/gardener.cloud/shoot/generic-kubeconfig",
+				"genericKubeconfigSecretName": "generic-token-kubeconfig",
+				"tokenSecretName":             "shoot-access-extension-admission-os-suse-chost",
+			},
+		})
+
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(container.Env).To(ContainElement(corev1.EnvVar{
+			Name:  "GARDEN_KUBECONFIG",
+			Value: "/var/run/secrets/gardener.cloud/shoot/generic-kubeconfig/kubeconfig",
+		}))
+		Expect(container.VolumeMounts).To(ConsistOf(corev1.VolumeMount{
+			Name:      "garden-kubeconfig",
+			MountPath: "/var/run/secrets/gardener.cloud/shoot/generic-kubeconfig",
+			ReadOnly:  true,
+		}))
+		Expect(deployment.Spec.Template.Spec.Volumes).To(ConsistOf(corev1.Volume{
+			Name: "garden-kubeconfig",
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					DefaultMode: ptr.To[int32](420),
+					Sources: []corev1.VolumeProjection{
+						{
+							Secret: &corev1.SecretProjection{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "generic-token-kubeconfig"},
+								Items:                []corev1.KeyToPath{{Key: "kubeconfig", Path: "kubeconfig"}},
+								Optional:             ptr.To(false),
+							},
+						},
+						{
+							Secret: &corev1.SecretProjection{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "shoot-access-extension-admission-os-suse-chost"},
+								Items:                []corev1.KeyToPath{{Key: "token", Path: "token"}},
+								Optional:             ptr.To(false),
+							},
+						},
+					},
+				},
+			},
+		}))
+	})
+
+	It("should fail if the projected kubeconfig is incomplete", func() {
+		_, err := renderer.RenderEmbeddedFS(Charts, RuntimeChartPath, "gardener-extension-admission-suse-chost", namespace, map[string]any{
+			"projectedKubeconfig": map[string]any{
+				"baseMountPath": "/var/run/secrets/gardener.cloud/shoot/generic-kubeconfig",
+			},
+		})
+		Expect(err).To(MatchError(ContainSubstring("genericKubeconfigSecretName is required")))
+	})
+})
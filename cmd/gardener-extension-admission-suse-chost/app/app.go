@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	controllercmd "github.com/gardener/gardener/extensions/pkg/controller/cmd"
+	"github.com/gardener/gardener/extensions/pkg/util"
+	extensionscmdwebhook "github.com/gardener/gardener/extensions/pkg/webhook/cmd"
+	gardencoreinstall "github.com/gardener/gardener/pkg/apis/core/install"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	gardenerhealthz "github.com/gardener/gardener/pkg/healthz"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/rest"
+	componentbaseconfigv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	admissioncmd "github.com/gardener/gardener-extension-os-suse-chost/pkg/admission/cmd"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/susechost"
+)
+
+// AdmissionName is the name of the admission component.
+const AdmissionName = "admission-" + susechost.ControllerName
+
+var log = runtimelog.Log.WithName("gardener-extension-admission-suse-chost")
+
+// NewAdmissionCommand returns a new command running the webhooks validating Shoots in the garden cluster.
+func NewAdmissionCommand(ctx context.Context) *cobra.Command {
+	var (
+		restOpts = &controllercmd.RESTOptions{}
+		mgrOpts  = &controllercmd.ManagerOptions{
+			LeaderElection:          true,
+			LeaderElectionID:        controllercmd.LeaderElectionNameID(AdmissionName),
+			LeaderElectionNamespace: os.Getenv("LEADER_ELECTION_NAMESPACE"),
+			WebhookServerPort:       443,
+			MetricsBindAddress:      ":8080",
+			HealthBindAddress:       ":8081",
+			WebhookCertDir:          "/tmp/admission-suse-chost-cert",
+		}
+
+		// options for the webhook server
+		webhookServerOptions = &extensionscmdwebhook.ServerOptions{
+			Namespace: os.Getenv("WEBHOOK_CONFIG_NAMESPACE"),
+		}
+		webhookSwitches = admissioncmd.GardenWebhookSwitchOptions()
+		webhookOptions  = extensionscmdwebhook.NewAddToManagerOptions(
+			AdmissionName,
+			"",
+			nil,
+			nil,
+			webhookServerOptions,
+			webhookSwitches,
+		)
+
+		aggOption = controllercmd.NewOptionAggregator(
+			restOpts,
+			mgrOpts,
+			webhookOptions,
+		)
+	)
+
+	cmd := &cobra.Command{
+		Use: AdmissionName,
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+			// The Shoots are validated in the garden cluster, while the certificates of the webhook server and the
+			// leader election are kept in the cluster the admission component runs in.
+			if gardenKubeconfig := os.Getenv("GARDEN_KUBECONFIG"); gardenKubeconfig != "" {
+				log.Info("Getting rest config for garden from GARDEN_KUBECONFIG", "path", gardenKubeconfig)
+				restOpts.Kubeconfig = gardenKubeconfig
+			}
+
+			if err := aggOption.Complete(); err != nil {
+				return fmt.Errorf("error completing options: %w", err)
+			}
+
+			util.ApplyClientConnectionConfigurationToRESTConfig(&componentbaseconfigv1alpha1.ClientConnectionConfiguration{
+				QPS:   100.0,
+				Burst: 130,
+			}, restOpts.Completed().Config)
+
+			managerOptions := mgrOpts.Completed().Options()
+
+			inClusterConfig, err := rest.InClusterConfig()
+			if err != nil {
+				return fmt.Errorf("could not get in-cluster config: %w", err)
+			}
+			managerOptions.LeaderElectionConfig = inClusterConfig
+
+			mgr, err := manager.New(restOpts.Completed().Config, managerOptions)
+			if err != nil {
+				return fmt.Errorf("could not instantiate manager: %w", err)
+			}
+
+			gardencoreinstall.Install(mgr.GetScheme())
+
+			sourceCluster, err := cluster.New(inClusterConfig, func(opts *cluster.Options) {
+				opts.Logger = log
+				opts.Cache.DefaultNamespaces = map[string]cache.Config{v1beta1constants.GardenNamespace: {}}
+			})
+			if err != nil {
+				return fmt.Errorf("could not instantiate source cluster: %w", err)
+			}
+
+			if err := mgr.AddHealthzCheck("source-informer-sync", gardenerhealthz.NewCacheSyncHealthzWithDeadline(mgr.GetLogger(), clock.RealClock{}, sourceCluster.GetCache(), gardenerhealthz.DefaultCacheSyncDeadline)); err != nil {
+				return fmt.Errorf("could not add healthcheck for source informers: %w", err)
+			}
+			if err := mgr.AddReadyzCheck("source-informer-sync", gardenerhealthz.NewCacheSyncHealthz(sourceCluster.GetCache())); err != nil {
+				return fmt.Errorf("could not add readycheck for source informers: %w", err)
+			}
+
+			if err := mgr.Add(sourceCluster); err != nil {
+				return fmt.Errorf("could not add source cluster to manager: %w", err)
+			}
+
+			if _, err := webhookOptions.Completed().AddToManager(ctx, mgr, sourceCluster); err != nil {
+				return fmt.Errorf("could not add webhooks to manager: %w", err)
+			}
+
+			if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+				return fmt.Errorf("could not add healthcheck: %w", err)
+			}
+			if err := mgr.AddHealthzCheck("informer-sync", gardenerhealthz.NewCacheSyncHealthzWithDeadline(mgr.GetLogger(), clock.RealClock{}, mgr.GetCache(), gardenerhealthz.DefaultCacheSyncDeadline)); err != nil {
+				return fmt.Errorf("could not add healthcheck for informers: %w", err)
+			}
+			if err := mgr.AddReadyzCheck("informer-sync", gardenerhealthz.NewCacheSyncHealthz(mgr.GetCache())); err != nil {
+				return fmt.Errorf("could not add readycheck for informers: %w", err)
+			}
+			if err := mgr.AddReadyzCheck("webhook-server", mgr.GetWebhookServer().StartedChecker()); err != nil {
+				return fmt.Errorf("could not add readycheck of webhook to manager: %w", err)
+			}
+
+			if err := mgr.Start(ctx); err != nil {
+				return fmt.Errorf("error running manager: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	aggOption.AddFlags(cmd.Flags())
+
+	return cmd
+}
@@ -22,6 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	suseCHostcmd "github.com/gardener/gardener-extension-os-suse-chost/pkg/cmd"
 	"github.com/gardener/gardener-extension-os-suse-chost/pkg/controller/operatingsystemconfig"
 	"github.com/gardener/gardener-extension-os-suse-chost/pkg/susechost"
 )
@@ -48,6 +49,8 @@ func NewControllerCommand(ctx context.Context) *cobra.Command {
 
 		reconcileOpts = &controllercmd.ReconcilerOptions{}
 
+		configFileOpts = &suseCHostcmd.ConfigOptions{}
+
 		controllerSwitches = controllercmd.NewSwitchOptions(
 			controllercmd.Switch(osccontroller.ControllerName, operatingsystemconfig.AddToManager),
 			controllercmd.Switch(heartbeat.ControllerName, heartbeat.AddToManager),
@@ -58,6 +61,7 @@ func NewControllerCommand(ctx context.Context) *cobra.Command {
 			restOpts,
 			mgrOpts,
 			ctrlOpts,
+			configFileOpts,
 			controllercmd.PrefixOption("heartbeat-", heartbeatCtrlOpts),
 			reconcileOpts,
 			controllerSwitches,
@@ -108,6 +112,7 @@ func NewControllerCommand(ctx context.Context) *cobra.Command {
 
 			reconcileOpts.Completed().Apply(&operatingsystemconfig.DefaultAddOptions.IgnoreOperationAnnotation)
 			operatingsystemconfig.DefaultAddOptions.ExtensionClasses = generalOpts.Completed().ExtensionClasses
+			configFileOpts.Completed().Apply(&operatingsystemconfig.DefaultAddOptions.Config)
 
 			if err := controllerSwitches.Completed().AddToManager(ctx, mgr); err != nil {
 				return fmt.Errorf("could not add controller to manager: %w", err)
@@ -123,5 +128,7 @@ func NewControllerCommand(ctx context.Context) *cobra.Command {
 
 	aggOption.AddFlags(cmd.Flags())
 
+	cmd.AddCommand(NewRenderCommand(ctx))
+
 	return cmd
 }
@@ -0,0 +1,309 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	runtimeutils "k8s.io/apimachinery/pkg/util/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
+	suseCHostcmd "github.com/gardener/gardener-extension-os-suse-chost/pkg/cmd"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/controller/operatingsystemconfig"
+)
+
+const (
+	// outputYAML prints all rendered artefacts as a single YAML document.
+	outputYAML = "yaml"
+	// outputUserData prints only the raw user data.
+	outputUserData = "user-data"
+)
+
+var renderScheme = runtime.NewScheme()
+
+func init() {
+	runtimeutils.Must(extensionsv1alpha1.AddToScheme(renderScheme))
+	runtimeutils.Must(corev1.AddToScheme(renderScheme))
+}
+
+// renderOptions are the command line options of the render command.
+type renderOptions struct {
+	// Files are the paths of the files containing the OperatingSystemConfig, Cluster and Secrets.
+	Files []string
+	// Purpose is the purpose the OperatingSystemConfig is rendered for. If empty, both purposes are rendered.
+	Purpose string
+	// Output is the output format.
+	Output string
+	// ConfigFile are the options for the controller configuration.
+	ConfigFile suseCHostcmd.ConfigOptions
+}
+
+// AddFlags adds the flags of the render command to the given flag set.
+func (o *renderOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVarP(&o.Files, "filename", "f", o.Files, "Files containing the OperatingSystemConfig, the Cluster and referenced Secrets (multi-document YAML is supported, '-' reads from stdin).")
+	fs.StringVar(&o.Purpose, "purpose", o.Purpose, fmt.Sprintf("Render the OperatingSystemConfig only for the given purpose (%q or %q). If empty, both purposes are rendered.", extensionsv1alpha1.OperatingSystemConfigPurposeProvision, extensionsv1alpha1.OperatingSystemConfigPurposeReconcile))
+	fs.StringVarP(&o.Output, "output", "o", outputYAML, fmt.Sprintf("Output format, either %q or %q.", outputYAML, outputUserData))
+	o.ConfigFile.AddFlags(fs)
+}
+
+// Validate validates the render options.
+func (o *renderOptions) Validate() error {
+	if len(o.Files) == 0 {
+		return errors.New("at least one file must be given with --filename")
+	}
+
+	switch extensionsv1alpha1.OperatingSystemConfigPurpose(o.Purpose) {
+	case "", extensionsv1alpha1.OperatingSystemConfigPurposeProvision, extensionsv1alpha1.OperatingSystemConfigPurposeReconcile:
+	default:
+		return fmt.Errorf("unknown purpose %q", o.Purpose)
+	}
+
+	switch o.Output {
+	case outputYAML, outputUserData:
+	default:
+		return fmt.Errorf("unknown output format %q", o.Output)
+	}
+
+	return nil
+}
+
+// renderResult is the rendered output of the actuator.
+type renderResult struct {
+	UserData                 string                    `json:"userData,omitempty"`
+	UserDataSize             int                       `json:"userDataSize,omitempty"`
+	UserDataCompression      string                    `json:"userDataCompression,omitempty"`
+	ProvisionScriptFragments []string                  `json:"provisionScriptFragments,omitempty"`
+	ExtensionUnits           []extensionsv1alpha1.Unit `json:"extensionUnits,omitempty"`
+	ExtensionFiles           []extensionsv1alpha1.File `json:"extensionFiles,omitempty"`
+
+	// RawUserData is the user data as returned by the actuator, it is only printed with the user-data output format.
+	RawUserData []byte `json:"-"`
+}
+
+// NewRenderCommand returns a new Command that renders the output of the OperatingSystemConfig actuator for objects
+// read from files, without the need of a running cluster.
+func NewRenderCommand(ctx context.Context) *cobra.Command {
+	opts := &renderOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Render the user data and extension files of an OperatingSystemConfig offline",
+		Long: `Render reads an OperatingSystemConfig, the Cluster of its namespace and all Secrets referenced by its files
+from the given files and prints what the extension would return for it: the user data (for purpose 'provision')
+and the extension units and files (for purpose 'reconcile').`,
+		Args: cobra.NoArgs,
+
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			return opts.run(ctx, cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *renderOptions) run(ctx context.Context, stdin io.Reader, out io.Writer) error {
+	osc, objects, err := o.readObjects(stdin)
+	if err != nil {
+		return err
+	}
+
+	if err := o.ConfigFile.Complete(); err != nil {
+		return err
+	}
+
+	var (
+		c        = fakeclient.NewClientBuilder().WithScheme(renderScheme).WithObjects(objects...).Build()
+		config   = *o.ConfigFile.Completed().Config
+		actuator = operatingsystemconfig.NewActuator(c, config)
+	)
+
+	purposes := []extensionsv1alpha1.OperatingSystemConfigPurpose{
+		extensionsv1alpha1.OperatingSystemConfigPurposeProvision,
+		extensionsv1alpha1.OperatingSystemConfigPurposeReconcile,
+	}
+	if o.Purpose != "" {
+		purposes = []extensionsv1alpha1.OperatingSystemConfigPurpose{extensionsv1alpha1.OperatingSystemConfigPurpose(o.Purpose)}
+	}
+
+	result := &renderResult{}
+	for _, purpose := range purposes {
+		oscForPurpose := osc.DeepCopy()
+		oscForPurpose.Spec.Purpose = purpose
+
+		userData, extensionUnits, extensionFiles, _, err := actuator.Reconcile(ctx, logr.Discard(), oscForPurpose)
+		if err != nil {
+			return fmt.Errorf("failed rendering OperatingSystemConfig for purpose %q: %w", purpose, err)
+		}
+
+		if len(userData) > 0 {
+			result.RawUserData = userData
+			result.UserDataSize = len(userData)
+			if result.UserData, result.UserDataCompression, err = decompressUserData(userData); err != nil {
+				return err
+			}
+			if result.ProvisionScriptFragments, err = operatingsystemconfig.ProvisionScriptFragments(ctx, c, config, oscForPurpose); err != nil {
+				return fmt.Errorf("failed determining the provision script fragments: %w", err)
+			}
+		}
+		result.ExtensionUnits = append(result.ExtensionUnits, extensionUnits...)
+		result.ExtensionFiles = append(result.ExtensionFiles, extensionFiles...)
+	}
+
+	if o.Output == outputUserData {
+		_, err := out.Write(result.RawUserData)
+		return err
+	}
+
+	data, err := yaml.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed marshalling render result: %w", err)
+	}
+
+	_, err = out.Write(data)
+	return err
+}
+
+// decompressUserData returns the given user data in plain text together with the compression applied to it, if any.
+func decompressUserData(userData []byte) (string, string, error) {
+	if !bytes.HasPrefix(userData, []byte{0x1f, 0x8b}) {
+		return string(userData), "", nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(userData))
+	if err != nil {
+		return "", "", fmt.Errorf("failed decompressing user data: %w", err)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", "", fmt.Errorf("failed decompressing user data: %w", err)
+	}
+
+	return string(data), string(config.UserDataCompressionGzip), nil
+}
+
+// readObjects decodes all objects from the configured files. It returns the single OperatingSystemConfig and all
+// other objects that must be served by the client used by the actuator.
+func (o *renderOptions) readObjects(stdin io.Reader) (*extensionsv1alpha1.OperatingSystemConfig, []client.Object, error) {
+	var (
+		decoder  = serializer.NewCodecFactory(renderScheme).UniversalDeserializer()
+		osc      *extensionsv1alpha1.OperatingSystemConfig
+		clusters []*extensionsv1alpha1.Cluster
+		secrets  []*corev1.Secret
+	)
+
+	for _, file := range o.Files {
+		documents, err := readYAMLDocuments(file, stdin)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, document := range documents {
+			obj, _, err := decoder.Decode(document, nil, nil)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed decoding object from %q: %w", file, err)
+			}
+
+			switch typed := obj.(type) {
+			case *extensionsv1alpha1.OperatingSystemConfig:
+				if osc != nil {
+					return nil, nil, fmt.Errorf("found more than one OperatingSystemConfig, but exactly one is required")
+				}
+				osc = typed
+			case *extensionsv1alpha1.Cluster:
+				clusters = append(clusters, typed)
+			case *corev1.Secret:
+				secrets = append(secrets, typed)
+			default:
+				return nil, nil, fmt.Errorf("unsupported object of type %T in %q", obj, file)
+			}
+		}
+	}
+
+	if osc == nil {
+		return nil, nil, errors.New("no OperatingSystemConfig found in the given files")
+	}
+	if len(clusters) > 1 {
+		return nil, nil, fmt.Errorf("found %d Clusters, but at most one is supported", len(clusters))
+	}
+
+	if osc.Namespace == "" {
+		osc.Namespace = "default"
+	}
+
+	var objects []client.Object
+	// The actuator looks up the Cluster by the namespace of the OperatingSystemConfig.
+	for _, cluster := range clusters {
+		cluster.Name = osc.Namespace
+		objects = append(objects, cluster)
+	}
+	// The actuator looks up Secrets referenced by files in the namespace of the OperatingSystemConfig.
+	for _, secret := range secrets {
+		if secret.Namespace == "" {
+			secret.Namespace = osc.Namespace
+		}
+		objects = append(objects, secret)
+	}
+
+	return osc, objects, nil
+}
+
+func readYAMLDocuments(file string, stdin io.Reader) ([][]byte, error) {
+	var in io.Reader = stdin
+
+	if file != "-" {
+		f, err := os.Open(file) // #nosec: G304 -- The file is explicitly given by the user.
+		if err != nil {
+			return nil, fmt.Errorf("failed opening %q: %w", file, err)
+		}
+		defer func() { _ = f.Close() }()
+		in = f
+	}
+
+	var (
+		documents [][]byte
+		reader    = utilyaml.NewYAMLReader(bufio.NewReader(in))
+	)
+
+	for {
+		document, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return documents, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed reading %q: %w", file, err)
+		}
+
+		if len(bytes.TrimSpace(document)) == 0 {
+			continue
+		}
+		documents = append(documents, document)
+	}
+}
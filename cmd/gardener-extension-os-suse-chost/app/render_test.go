@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	. "github.com/gardener/gardener-extension-os-suse-chost/cmd/gardener-extension-os-suse-chost/app"
+)
+
+const (
+	oscYAML = `apiVersion: extensions.gardener.cloud/v1alpha1
+kind: OperatingSystemConfig
+metadata:
+  name: pool-01
+  namespace: shoot--foo--bar
+spec:
+  type: suse-chost
+  purpose: provision
+  units:
+  - name: some-unit
+    content: foo
+  files:
+  - path: /some/file
+    content:
+      secretRef:
+        name: some-secret
+        dataKey: some-key
+`
+	clusterYAML = `apiVersion: extensions.gardener.cloud/v1alpha1
+kind: Cluster
+metadata:
+  name: ignored
+spec:
+  cloudProfile:
+    apiVersion: core.gardener.cloud/v1beta1
+    kind: CloudProfile
+  seed:
+    apiVersion: core.gardener.cloud/v1beta1
+    kind: Seed
+  shoot:
+    apiVersion: core.gardener.cloud/v1beta1
+    kind: Shoot
+    spec:
+      kubernetes:
+        version: 1.35.0
+`
+	secretYAML = `apiVersion: v1
+kind: Secret
+metadata:
+  name: some-secret
+data:
+  some-key: YmFy
+`
+)
+
+var _ = Describe("Render", func() {
+	var (
+		ctx = context.TODO()
+
+		dir    string
+		stdout *bytes.Buffer
+		render func(args ...string) error
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "osc.yaml"), []byte(oscYAML), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "cluster.yaml"), []byte(clusterYAML+"---\n"+secretYAML), 0600)).To(Succeed())
+
+		stdout = &bytes.Buffer{}
+		render = func(args ...string) error {
+			cmd := NewRenderCommand(ctx)
+			cmd.SetArgs(args)
+			cmd.SetOut(stdout)
+			cmd.SetErr(&bytes.Buffer{})
+			return cmd.Execute()
+		}
+	})
+
+	It("should render the user data and the extension files", func() {
+		Expect(render("-f", filepath.Join(dir, "osc.yaml"), "-f", filepath.Join(dir, "cluster.yaml"))).To(Succeed())
+
+		result := map[string]any{}
+		Expect(yaml.Unmarshal(stdout.Bytes(), &result)).To(Succeed())
+
+		Expect(result["userData"]).To(And(
+			HavePrefix("#!/bin/bash\n"),
+			// base64 of the secret data "bar"
+			ContainSubstring("YmFy"),
+			ContainSubstring("systemctl enable 'some-unit'"),
+		))
+		Expect(result["extensionFiles"]).To(ContainElement(HaveKeyWithValue("path", "/var/lib/kubelet/extra_args")))
+		Expect(result["provisionScriptFragments"]).To(Equal([]any{"files", "packages", "node", "docker", "containerd", "journald", "units"}))
+	})
+
+	It("should only print the raw user data", func() {
+		Expect(render("-f", filepath.Join(dir, "osc.yaml"), "-f", filepath.Join(dir, "cluster.yaml"), "--purpose", "provision", "-o", "user-data")).To(Succeed())
+
+		Expect(stdout.String()).To(And(
+			HavePrefix("#!/bin/bash\n"),
+			HaveSuffix("touch /var/lib/osc/provision-osc-applied\n"),
+		))
+	})
+
+	It("should print compressed user data decompressed", func() {
+		Expect(os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(`apiVersion: suse-chost.os.extensions.config.gardener.cloud/v1alpha1
+kind: ControllerConfiguration
+userData:
+  maxSize: 4Ki
+  compression: gzip
+`), 0600)).To(Succeed())
+
+		Expect(render("-f", filepath.Join(dir, "osc.yaml"), "-f", filepath.Join(dir, "cluster.yaml"), "--purpose", "provision", "--config-file", filepath.Join(dir, "config.yaml"))).To(Succeed())
+
+		result := map[string]any{}
+		Expect(yaml.Unmarshal(stdout.Bytes(), &result)).To(Succeed())
+
+		Expect(result["userData"]).To(HavePrefix("#!/bin/bash\n"))
+		Expect(result["userDataCompression"]).To(Equal("gzip"))
+		Expect(result["userDataSize"]).To(BeNumerically("<=", 4096))
+	})
+
+	It("should fail if a referenced secret is missing", func() {
+		Expect(os.WriteFile(filepath.Join(dir, "cluster-only.yaml"), []byte(clusterYAML), 0600)).To(Succeed())
+
+		Expect(render("-f", filepath.Join(dir, "osc.yaml"), "-f", filepath.Join(dir, "cluster-only.yaml"), "--purpose", "provision")).To(MatchError(ContainSubstring(`secrets "some-secret" not found`)))
+	})
+
+	It("should fail if the Cluster is missing", func() {
+		Expect(render("-f", filepath.Join(dir, "osc.yaml"), "--purpose", "provision")).To(MatchError(ContainSubstring("failed to get cluster")))
+	})
+
+	It("should fail if no OperatingSystemConfig is given", func() {
+		Expect(render("-f", filepath.Join(dir, "cluster.yaml"))).To(MatchError(ContainSubstring("no OperatingSystemConfig found")))
+	})
+
+	It("should fail for an unknown purpose", func() {
+		Expect(render("-f", filepath.Join(dir, "osc.yaml"), "--purpose", "foo")).To(MatchError(`unknown purpose "foo"`))
+	})
+})
@@ -0,0 +1,284 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
+	gardencorehelper "github.com/gardener/gardener/pkg/api/core/helper"
+	"github.com/gardener/gardener/pkg/apis/core"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	memoryonechosthelper "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/helper"
+	memoryonechostvalidation "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/validation"
+	susechostapi "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost"
+	susechostvalidation "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost/validation"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/memoryone"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/susechost"
+)
+
+// Warner returns warnings for objects which are admitted nevertheless.
+type Warner interface {
+	// Warnings returns the warnings for the given object. oldObj is only set on updates.
+	Warnings(ctx context.Context, newObj, oldObj client.Object) []string
+}
+
+type shootValidator struct{}
+
+// NewShootValidator returns a new instance of a Shoot validator which validates the worker pools using the
+// suse-chost or memoryone-chost OS. It also implements Warner.
+func NewShootValidator() extensionswebhook.Validator {
+	return &shootValidator{}
+}
+
+// Validate validates the given Shoot object.
+func (s *shootValidator) Validate(_ context.Context, newObj, oldObj client.Object) error {
+	newShoot, ok := newObj.(*core.Shoot)
+	if !ok {
+		return fmt.Errorf("expected Shoot, but got %T", newObj)
+	}
+	var oldShoot *core.Shoot
+	if oldObj != nil {
+		oldShoot, ok = oldObj.(*core.Shoot)
+		if !ok {
+			return fmt.Errorf("expected Shoot, but got %T", oldObj)
+		}
+	}
+
+	if newShoot.DeletionTimestamp != nil {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	workersPath := field.NewPath("spec", "provider", "workers")
+
+	for i, worker := range newShoot.Spec.Provider.Workers {
+		if worker.Machine.Image == nil || !isCHostImage(worker.Machine.Image.Name) {
+			continue
+		}
+
+		var (
+			workerPath = workersPath.Index(i)
+			oldWorker  = findWorker(oldShoot, worker.Name)
+		)
+
+		allErrs = append(allErrs, validateKubernetesVersion(newShoot, &worker, oldShoot, oldWorker, workerPath)...)
+
+		// Only validate worker pools which are new or whose OS related configuration has changed, so that existing
+		// Shoots are not blocked from unrelated updates.
+		if !workerChanged(&worker, oldWorker) {
+			continue
+		}
+
+		switch worker.Machine.Image.Name {
+		case memoryone.OSTypeMemoryOneCHost:
+			allErrs = append(allErrs, validateMemoryOneCHostWorker(newShoot, worker, workerPath)...)
+		case susechost.OSTypeSuSECHost:
+			allErrs = append(allErrs, validateSuSECHostWorker(newShoot, worker, workerPath)...)
+		}
+	}
+
+	return allErrs.ToAggregate()
+}
+
+// Warnings returns warnings for the vSMP parameters of the memoryone-chost worker pools of the given Shoot which are
+// unknown or out of range, see helper.VsmpCatalogWarnings. Like validation, it only considers worker pools which are
+// new or whose OS related configuration has changed.
+func (s *shootValidator) Warnings(_ context.Context, newObj, oldObj client.Object) []string {
+	newShoot, ok := newObj.(*core.Shoot)
+	if !ok || newShoot.DeletionTimestamp != nil {
+		return nil
+	}
+	oldShoot, _ := oldObj.(*core.Shoot)
+
+	var (
+		warnings    []string
+		workersPath = field.NewPath("spec", "provider", "workers")
+	)
+
+	for i, worker := range newShoot.Spec.Provider.Workers {
+		if worker.Machine.Image == nil || worker.Machine.Image.Name != memoryone.OSTypeMemoryOneCHost || worker.Machine.Image.ProviderConfig == nil ||
+			!workerChanged(&worker, findWorker(oldShoot, worker.Name)) {
+			continue
+		}
+
+		// Provider configs which cannot be decoded are rejected by the validation.
+		config, err := memoryone.DecodeConfiguration(worker.Machine.Image.ProviderConfig.Raw)
+		if err != nil {
+			continue
+		}
+
+		providerConfigPath := workersPath.Index(i).Child("machine", "image", "providerConfig")
+		for _, warning := range memoryonechosthelper.VsmpCatalogWarnings(config) {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", providerConfigPath, warning))
+		}
+	}
+
+	return warnings
+}
+
+// workerChanged returns whether the given worker pool is new or its OS related configuration has changed.
+func workerChanged(worker, oldWorker *core.Worker) bool {
+	return oldWorker == nil ||
+		!apiequality.Semantic.DeepEqual(oldWorker.Machine.Image, worker.Machine.Image) ||
+		!apiequality.Semantic.DeepEqual(oldWorker.DataVolumes, worker.DataVolumes)
+}
+
+// validateKubernetesVersion prevents worker pools running CHost with cgroup v1 from being created with or updated to
+// a Kubernetes version whose kubelet no longer supports cgroup v1. Worker pools which already run such a version are
+// left alone, so that they are not blocked from unrelated updates.
+func validateKubernetesVersion(shoot *core.Shoot, worker *core.Worker, oldShoot *core.Shoot, oldWorker *core.Worker, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// suse-chost nodes can boot with the unified cgroup hierarchy (cgroup v2) instead. Enabling it only affects new nodes,
+	// but the nodes of a worker pool are replaced when it is upgraded to a new minor version, unless they are updated
+	// in-place.
+	if worker.Machine.Image.Name == susechost.OSTypeSuSECHost && susechost.UnifiedCgroupHierarchyEnabled(suseCHostConfiguration(worker), shoot.Annotations) &&
+		!gardencorehelper.IsUpdateStrategyInPlace(worker.UpdateStrategy) {
+		return allErrs
+	}
+
+	version, versionPath := kubernetesVersion(shoot, worker, fldPath)
+	if !cgroupV1Removed(version) {
+		return allErrs
+	}
+
+	if oldWorker != nil && oldWorker.Machine.Image != nil && isCHostImage(oldWorker.Machine.Image.Name) {
+		if oldVersion, _ := kubernetesVersion(oldShoot, oldWorker, fldPath); cgroupV1Removed(oldVersion) {
+			return allErrs
+		}
+	}
+
+	allErrs = append(allErrs, field.Forbidden(versionPath, fmt.Sprintf("worker pool %q uses the %s OS which runs cgroup v1, but kubelet drops the support for cgroup v1 with Kubernetes %s (KEP-5573). "+
+		"Keep the worker pool on a Kubernetes version lower than %s until it has been migrated to cgroup v2 (unified hierarchy), "+
+		"or move its workload to a worker pool running an operating system with cgroup v2 before upgrading. "+
+		"suse-chost worker pools which are not updated in-place are migrated by setting unifiedCgroupHierarchy to true in %s before upgrading",
+		worker.Name, worker.Machine.Image.Name, susechost.KubeletCgroupV1RemovedVersion.Original(), susechost.KubeletCgroupV1RemovedVersion.Original(), fldPath.Child("machine", "image", "providerConfig"))))
+
+	return allErrs
+}
+
+// suseCHostConfiguration returns the suse-chost provider configuration of the given worker pool, or nil if it has none or
+// it cannot be decoded, which is rejected by validateSuSECHostWorker.
+func suseCHostConfiguration(worker *core.Worker) *susechostapi.OperatingSystemConfiguration {
+	if worker.Machine.Image.ProviderConfig == nil {
+		return nil
+	}
+
+	config, err := susechost.DecodeConfiguration(worker.Machine.Image.ProviderConfig.Raw)
+	if err != nil {
+		return nil
+	}
+	return config
+}
+
+// kubernetesVersion returns the Kubernetes version of the given worker pool, i.e. the version of the worker pool if
+// it overrides it, or the version of the Shoot otherwise, together with the path of the field it is taken from.
+func kubernetesVersion(shoot *core.Shoot, worker *core.Worker, fldPath *field.Path) (string, *field.Path) {
+	if worker.Kubernetes != nil && worker.Kubernetes.Version != nil {
+		return *worker.Kubernetes.Version, fldPath.Child("kubernetes", "version")
+	}
+
+	return shoot.Spec.Kubernetes.Version, field.NewPath("spec", "kubernetes", "version")
+}
+
+func cgroupV1Removed(version string) bool {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		// invalid versions are rejected by the validation of gardener-apiserver
+		return false
+	}
+
+	return !v.LessThan(susechost.KubeletCgroupV1RemovedVersion)
+}
+
+func isCHostImage(name string) bool {
+	return name == susechost.OSTypeSuSECHost || name == memoryone.OSTypeMemoryOneCHost
+}
+
+func validateSuSECHostWorker(shoot *core.Shoot, worker core.Worker, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	providerConfig := worker.Machine.Image.ProviderConfig
+	if providerConfig == nil {
+		return allErrs
+	}
+
+	providerConfigPath := fldPath.Child("machine", "image", "providerConfig")
+
+	config, err := susechost.DecodeConfiguration(providerConfig.Raw)
+	if err != nil {
+		return append(allErrs, field.Invalid(providerConfigPath, string(providerConfig.Raw), err.Error()))
+	}
+
+	allErrs = append(allErrs, susechostvalidation.ValidateOperatingSystemConfiguration(config, providerConfigPath)...)
+
+	// Credentials of repositories are read from the resources referenced by the Shoot, hence they must exist there.
+	for i, repository := range config.Repositories {
+		if repository.CredentialsResourceName == nil || len(*repository.CredentialsResourceName) == 0 {
+			continue
+		}
+		if gardencorehelper.GetResourceByName(shoot.Spec.Resources, *repository.CredentialsResourceName) == nil {
+			allErrs = append(allErrs, field.Invalid(providerConfigPath.Child("repositories").Index(i).Child("credentialsResourceName"), *repository.CredentialsResourceName, "resource must be referenced in spec.resources of the Shoot"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateMemoryOneCHostWorker(shoot *core.Shoot, worker core.Worker, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// The memoryone-chost image only contains the hypervisor, the actual CHost OS is booted from a data volume which
+	// is created from a snapshot of a CHost volume.
+	if len(worker.DataVolumes) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("dataVolumes"), fmt.Sprintf("worker pools using the %s OS require a data volume holding the CHost snapshot", memoryone.OSTypeMemoryOneCHost)))
+	}
+
+	providerConfig := worker.Machine.Image.ProviderConfig
+	if providerConfig == nil {
+		return allErrs
+	}
+
+	providerConfigPath := fldPath.Child("machine", "image", "providerConfig")
+
+	config, err := memoryone.DecodeConfiguration(providerConfig.Raw)
+	if err != nil {
+		return append(allErrs, field.Invalid(providerConfigPath, string(providerConfig.Raw), err.Error()))
+	}
+
+	allErrs = append(allErrs, memoryonechostvalidation.ValidateOperatingSystemConfiguration(config, providerConfigPath)...)
+
+	// The content of additional parts is read from the resources referenced by the Shoot, hence they must exist there.
+	for i, part := range config.AdditionalParts {
+		if part.SecretRef == nil || len(part.SecretRef.ResourceName) == 0 {
+			continue
+		}
+		if gardencorehelper.GetResourceByName(shoot.Spec.Resources, part.SecretRef.ResourceName) == nil {
+			allErrs = append(allErrs, field.Invalid(providerConfigPath.Child("additionalParts").Index(i).Child("secretRef", "resourceName"), part.SecretRef.ResourceName, "resource must be referenced in spec.resources of the Shoot"))
+		}
+	}
+
+	return allErrs
+}
+
+func findWorker(shoot *core.Shoot, name string) *core.Worker {
+	if shoot == nil {
+		return nil
+	}
+
+	for i := range shoot.Spec.Provider.Workers {
+		if shoot.Spec.Provider.Workers[i].Name == name {
+			return &shoot.Spec.Provider.Workers[i]
+		}
+	}
+
+	return nil
+}
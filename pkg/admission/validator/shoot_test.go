@@ -0,0 +1,340 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validator_test
+
+import (
+	"context"
+
+	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
+	"github.com/gardener/gardener/pkg/apis/core"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/admission/validator"
+)
+
+var _ = Describe("Shoot validator", func() {
+	var (
+		ctx = context.Background()
+
+		shootValidator extensionswebhook.Validator
+		shoot          *core.Shoot
+	)
+
+	BeforeEach(func() {
+		shootValidator = validator.NewShootValidator()
+		shoot = &core.Shoot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shoot-1",
+				Namespace: "garden-dev",
+			},
+			Spec: core.ShootSpec{
+				Kubernetes: core.Kubernetes{Version: "1.37.2"},
+				Provider: core.Provider{
+					Workers: []core.Worker{
+						{
+							Name: "worker-1",
+							Machine: core.Machine{
+								Image: &core.ShootMachineImage{Name: "suse-chost", Version: "15.6.20250101"},
+							},
+						},
+						{
+							Name: "worker-2",
+							Machine: core.Machine{
+								Image: &core.ShootMachineImage{
+									Name:    "memoryone-chost",
+									Version: "9.5.195",
+									ProviderConfig: &runtime.RawExtension{Raw: []byte(`apiVersion: memoryone-chost.os.extensions.gardener.cloud/v1alpha1
+kind: OperatingSystemConfiguration
+vsmpConfiguration:
+  mem_topology: "3"
+  system_memory: "7x"
+`)},
+								},
+							},
+							DataVolumes: []core.DataVolume{{Name: "chost", VolumeSize: "50Gi"}},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	Describe("#Validate", func() {
+		It("should succeed for valid worker pools", func() {
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+		})
+
+		It("should succeed for a memoryone-chost worker pool without provider config", func() {
+			shoot.Spec.Provider.Workers[1].Machine.Image.ProviderConfig = nil
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+		})
+
+		It("should ignore Shoots which are being deleted", func() {
+			shoot.DeletionTimestamp = &metav1.Time{}
+			shoot.Spec.Provider.Workers[1].DataVolumes = nil
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+		})
+
+		It("should fail for a memoryone-chost worker pool without data volume", func() {
+			shoot.Spec.Provider.Workers[1].DataVolumes = nil
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring("spec.provider.workers[1].dataVolumes: Required value: worker pools using the memoryone-chost OS require a data volume holding the CHost snapshot")))
+		})
+
+		It("should fail for an unknown field in the memoryone-chost provider config", func() {
+			shoot.Spec.Provider.Workers[1].Machine.Image.ProviderConfig.Raw = []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","vsmpConfig":{}}`)
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(And(
+				ContainSubstring("spec.provider.workers[1].machine.image.providerConfig"),
+				ContainSubstring(`unknown field "vsmpConfig"`),
+			)))
+		})
+
+		It("should fail for an invalid memoryone-chost provider config", func() {
+			shoot.Spec.Provider.Workers[1].Machine.Image.ProviderConfig.Raw = []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","vsmpConfiguration":{"foo":"bar; baz=1"}}`)
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring("spec.provider.workers[1].machine.image.providerConfig.vsmpConfiguration[foo]: Invalid value: \"bar; baz=1\": value must not contain ';'")))
+		})
+
+		It("should succeed for a valid v1beta1 memoryone-chost provider config", func() {
+			shoot.Spec.Provider.Workers[1].Machine.Image.ProviderConfig.Raw = []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1beta1","kind":"OperatingSystemConfiguration","memTopology":3,"systemMemoryMultiplier":7,"vsmpConfiguration":{"foo":"bar"}}`)
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+		})
+
+		It("should fail for an invalid v1beta1 memoryone-chost provider config", func() {
+			shoot.Spec.Provider.Workers[1].Machine.Image.ProviderConfig.Raw = []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1beta1","kind":"OperatingSystemConfiguration","systemMemoryMultiplier":-7}`)
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring("spec.provider.workers[1].machine.image.providerConfig.vsmpConfiguration[system_memory]: Invalid value: \"-7x\": value must be a multiplier, e.g. 6x")))
+		})
+
+		It("should succeed for an unknown vSMP parameter in the memoryone-chost provider config", func() {
+			shoot.Spec.Provider.Workers[1].Machine.Image.ProviderConfig.Raw = []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1beta1","kind":"OperatingSystemConfiguration","vsmpConfiguration":{"mem_topolgy":"3"}}`)
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+		})
+
+		It("should fail for a malformed value of a known vSMP parameter in the memoryone-chost provider config", func() {
+			shoot.Spec.Provider.Workers[1].Machine.Image.ProviderConfig.Raw = []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1beta1","kind":"OperatingSystemConfiguration","vsmpConfiguration":{"debug_features":"0xff"}}`)
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring(`spec.provider.workers[1].machine.image.providerConfig.vsmpConfiguration[debug_features]: Invalid value: "0xff": value must be a hex mask of at most 64 bits, e.g. &0xff`)))
+		})
+
+		It("should succeed for a memoryone-chost additional part whose content is referenced by the Shoot", func() {
+			shoot.Spec.Resources = []core.NamedResourceReference{{Name: "hypervisor-tooling"}}
+			shoot.Spec.Provider.Workers[1].Machine.Image.ProviderConfig.Raw = []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","additionalParts":[{"contentType":"text/x-shellscript","secretRef":{"resourceName":"hypervisor-tooling","dataKey":"install.sh"}}]}`)
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+		})
+
+		It("should fail for a memoryone-chost additional part whose content is not referenced by the Shoot", func() {
+			shoot.Spec.Provider.Workers[1].Machine.Image.ProviderConfig.Raw = []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","additionalParts":[{"contentType":"text/x-shellscript","secretRef":{"resourceName":"hypervisor-tooling","dataKey":"install.sh"}}]}`)
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring(`spec.provider.workers[1].machine.image.providerConfig.additionalParts[0].secretRef.resourceName: Invalid value: "hypervisor-tooling"`)))
+		})
+
+		It("should succeed for a valid suse-chost provider config", func() {
+			shoot.Spec.Provider.Workers[0].Machine.Image.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration"}`)}
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+		})
+
+		It("should fail for an unknown field in the suse-chost provider config", func() {
+			shoot.Spec.Provider.Workers[0].Machine.Image.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","foo":"bar"}`)}
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(And(
+				ContainSubstring("spec.provider.workers[0].machine.image.providerConfig"),
+				ContainSubstring(`unknown field "foo"`),
+			)))
+		})
+
+		It("should succeed for a suse-chost repository whose credentials are referenced by the Shoot", func() {
+			shoot.Spec.Resources = []core.NamedResourceReference{{Name: "mirror-credentials"}}
+			shoot.Spec.Provider.Workers[0].Machine.Image.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","repositories":[{"name":"mirror","url":"http://mirror.internal/sles","credentialsResourceName":"mirror-credentials"}]}`)}
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+		})
+
+		It("should fail for a suse-chost repository whose credentials are not referenced by the Shoot", func() {
+			shoot.Spec.Provider.Workers[0].Machine.Image.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","repositories":[{"name":"mirror","url":"http://mirror.internal/sles","credentialsResourceName":"mirror-credentials"}]}`)}
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring(`spec.provider.workers[0].machine.image.providerConfig.repositories[0].credentialsResourceName: Invalid value: "mirror-credentials"`)))
+		})
+
+		It("should fail for a provider config of another OS in a suse-chost worker pool", func() {
+			shoot.Spec.Provider.Workers[0].Machine.Image.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration"}`)}
+
+			Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(ContainSubstring("spec.provider.workers[0].machine.image.providerConfig")))
+		})
+
+		It("should not validate unchanged worker pools on update", func() {
+			shoot.Spec.Provider.Workers[1].DataVolumes = nil
+			oldShoot := shoot.DeepCopy()
+			shoot.Spec.Provider.Workers[1].Maximum = 3
+
+			Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(Succeed())
+		})
+
+		It("should validate changed worker pools on update", func() {
+			oldShoot := shoot.DeepCopy()
+			shoot.Spec.Provider.Workers[1].DataVolumes = nil
+
+			Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(MatchError(ContainSubstring("spec.provider.workers[1].dataVolumes")))
+		})
+
+		Context("Kubernetes version", func() {
+			It("should forbid creating a Shoot with Kubernetes 1.38", func() {
+				shoot.Spec.Kubernetes.Version = "1.38.0"
+
+				Expect(shootValidator.Validate(ctx, shoot, nil)).To(MatchError(And(
+					ContainSubstring(`spec.kubernetes.version: Forbidden: worker pool "worker-1" uses the suse-chost OS which runs cgroup v1`),
+					ContainSubstring(`worker pool "worker-2" uses the memoryone-chost OS which runs cgroup v1`),
+					ContainSubstring("migrated to cgroup v2"),
+				)))
+			})
+
+			It("should forbid upgrading a Shoot to Kubernetes 1.38", func() {
+				oldShoot := shoot.DeepCopy()
+				shoot.Spec.Kubernetes.Version = "1.38.1"
+
+				Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(MatchError(ContainSubstring("spec.kubernetes.version: Forbidden")))
+			})
+
+			It("should forbid upgrading a worker pool to Kubernetes 1.38", func() {
+				oldShoot := shoot.DeepCopy()
+				shoot.Spec.Provider.Workers[0].Kubernetes = &core.WorkerKubernetes{Version: ptr.To("1.38.0")}
+
+				Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(MatchError(And(
+					ContainSubstring(`spec.provider.workers[0].kubernetes.version: Forbidden: worker pool "worker-1"`),
+					Not(ContainSubstring(`worker pool "worker-2"`)),
+				)))
+			})
+
+			It("should forbid switching a worker pool running Kubernetes 1.38 to suse-chost", func() {
+				shoot.Spec.Kubernetes.Version = "1.38.0"
+				shoot.Spec.Provider.Workers = shoot.Spec.Provider.Workers[:1]
+				oldShoot := shoot.DeepCopy()
+				oldShoot.Spec.Provider.Workers[0].Machine.Image.Name = "gardenlinux"
+
+				Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(MatchError(ContainSubstring("spec.kubernetes.version: Forbidden")))
+			})
+
+			It("should allow Kubernetes 1.38 for suse-chost worker pools using the unified cgroup hierarchy", func() {
+				metav1.SetMetaDataAnnotation(&shoot.ObjectMeta, "suse-chost.os.extensions.gardener.cloud/unified-cgroup-hierarchy", "true")
+				oldShoot := shoot.DeepCopy()
+				shoot.Spec.Kubernetes.Version = "1.38.0"
+
+				Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(MatchError(And(
+					Not(ContainSubstring(`worker pool "worker-1"`)),
+					ContainSubstring(`worker pool "worker-2" uses the memoryone-chost OS which runs cgroup v1`),
+				)))
+			})
+
+			It("should allow Kubernetes 1.38 for suse-chost worker pools enabling the unified cgroup hierarchy in the provider config", func() {
+				shoot.Spec.Provider.Workers[0].Machine.Image.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","unifiedCgroupHierarchy":true}`)}
+				oldShoot := shoot.DeepCopy()
+				shoot.Spec.Kubernetes.Version = "1.38.0"
+
+				Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(MatchError(And(
+					Not(ContainSubstring(`worker pool "worker-1"`)),
+					ContainSubstring(`worker pool "worker-2" uses the memoryone-chost OS which runs cgroup v1`),
+				)))
+			})
+
+			It("should forbid Kubernetes 1.38 for suse-chost worker pools disabling the unified cgroup hierarchy in the provider config despite the annotation", func() {
+				metav1.SetMetaDataAnnotation(&shoot.ObjectMeta, "suse-chost.os.extensions.gardener.cloud/unified-cgroup-hierarchy", "true")
+				shoot.Spec.Provider.Workers[0].Machine.Image.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","unifiedCgroupHierarchy":false}`)}
+				oldShoot := shoot.DeepCopy()
+				shoot.Spec.Kubernetes.Version = "1.38.0"
+
+				Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(MatchError(
+					ContainSubstring(`worker pool "worker-1" uses the suse-chost OS which runs cgroup v1`),
+				))
+			})
+
+			It("should forbid Kubernetes 1.38 for suse-chost worker pools updated in-place even if they use the unified cgroup hierarchy", func() {
+				metav1.SetMetaDataAnnotation(&shoot.ObjectMeta, "suse-chost.os.extensions.gardener.cloud/unified-cgroup-hierarchy", "true")
+				shoot.Spec.Provider.Workers[0].UpdateStrategy = ptr.To(core.AutoInPlaceUpdate)
+				oldShoot := shoot.DeepCopy()
+				shoot.Spec.Kubernetes.Version = "1.38.0"
+
+				Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(MatchError(
+					ContainSubstring(`worker pool "worker-1" uses the suse-chost OS which runs cgroup v1`),
+				))
+			})
+
+			It("should allow Kubernetes 1.38 for worker pools not using CHost", func() {
+				shoot.Spec.Kubernetes.Version = "1.38.0"
+				shoot.Spec.Provider.Workers = shoot.Spec.Provider.Workers[:1]
+				shoot.Spec.Provider.Workers[0].Machine.Image.Name = "gardenlinux"
+
+				Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+			})
+
+			It("should allow a CHost worker pool to stay on an older version while the Shoot is upgraded", func() {
+				oldShoot := shoot.DeepCopy()
+				shoot.Spec.Kubernetes.Version = "1.38.0"
+				for i := range shoot.Spec.Provider.Workers {
+					shoot.Spec.Provider.Workers[i].Kubernetes = &core.WorkerKubernetes{Version: ptr.To("1.37.2")}
+				}
+
+				Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(Succeed())
+			})
+
+			It("should not block updates of worker pools already running Kubernetes 1.38", func() {
+				shoot.Spec.Kubernetes.Version = "1.38.0"
+				oldShoot := shoot.DeepCopy()
+				shoot.Spec.Kubernetes.Version = "1.38.1"
+
+				Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(Succeed())
+			})
+		})
+
+		It("should fail for unexpected objects", func() {
+			Expect(shootValidator.Validate(ctx, &core.Seed{}, nil)).To(MatchError(ContainSubstring("expected Shoot")))
+		})
+	})
+
+	Describe("#Warnings", func() {
+		var warner validator.Warner
+
+		BeforeEach(func() {
+			warner = shootValidator.(validator.Warner)
+			shoot.Spec.Provider.Workers[1].Machine.Image.ProviderConfig.Raw = []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","vsmpConfiguration":{"mem_topology":"9","mem_topolgy":"3","foo":"bar"},"experimentalVsmpParameters":["foo"]}`)
+		})
+
+		It("should warn about unknown and out of range vSMP parameters", func() {
+			Expect(warner.Warnings(ctx, shoot, nil)).To(Equal([]string{
+				`spec.provider.workers[1].machine.image.providerConfig: unknown parameter mem_topolgy, it is passed to the hypervisor anyway`,
+				`spec.provider.workers[1].machine.image.providerConfig: parameter mem_topology="9": value must be between 1 and 8, it is passed to the hypervisor anyway`,
+			}))
+		})
+
+		It("should not warn about valid vSMP parameters", func() {
+			shoot.Spec.Provider.Workers[1].Machine.Image.ProviderConfig.Raw = []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","vsmpConfiguration":{"mem_topology":"8","system_memory":"16x"}}`)
+
+			Expect(warner.Warnings(ctx, shoot, nil)).To(BeEmpty())
+		})
+
+		It("should not warn about unchanged worker pools on update", func() {
+			oldShoot := shoot.DeepCopy()
+			shoot.Spec.Provider.Workers[1].Maximum = 3
+
+			Expect(warner.Warnings(ctx, shoot, oldShoot)).To(BeEmpty())
+		})
+
+		It("should not warn about Shoots which are being deleted", func() {
+			shoot.DeletionTimestamp = &metav1.Time{}
+
+			Expect(warner.Warnings(ctx, shoot, nil)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validator
+
+import (
+	"context"
+
+	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
+	"github.com/gardener/gardener/pkg/apis/core"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/memoryone"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/susechost"
+)
+
+const (
+	// Name is a name for a validation webhook.
+	Name = "validator"
+	// SuSECHostValidatorName is the name of the validation webhook for Shoots using the suse-chost OS.
+	SuSECHostValidatorName = susechost.OSTypeSuSECHost + "." + Name
+	// MemoryOneCHostValidatorName is the name of the validation webhook for Shoots using the memoryone-chost OS.
+	MemoryOneCHostValidatorName = memoryone.OSTypeMemoryOneCHost + "." + Name
+)
+
+var logger = log.Log.WithName("os-suse-chost-validator-webhook")
+
+// NewSuSECHostWebhook creates a new webhook that validates Shoots using the suse-chost OS.
+func NewSuSECHostWebhook(mgr manager.Manager) (*extensionswebhook.Webhook, error) {
+	return newWebhook(mgr, SuSECHostValidatorName, susechost.OSTypeSuSECHost)
+}
+
+// NewMemoryOneCHostWebhook creates a new webhook that validates Shoots using the memoryone-chost OS.
+func NewMemoryOneCHostWebhook(mgr manager.Manager) (*extensionswebhook.Webhook, error) {
+	return newWebhook(mgr, MemoryOneCHostValidatorName, memoryone.OSTypeMemoryOneCHost)
+}
+
+// newWebhook creates a Shoot validation webhook for the given OS type. Label selectors cannot express that any of
+// several labels is present, hence one webhook is registered per OS type. The webhooks are served by the admission
+// component whose manager is connected to the garden cluster, hence they target the cluster of the manager.
+func newWebhook(mgr manager.Manager, name, osType string) (*extensionswebhook.Webhook, error) {
+	logger.Info("Setting up webhook", "name", name)
+
+	validator := &shootValidator{}
+	webhook, err := extensionswebhook.New(mgr, extensionswebhook.Args{
+		Name: name,
+		Path: "/webhooks/validate/" + osType,
+		Validators: map[extensionswebhook.Validator][]extensionswebhook.Type{
+			validator: {{Obj: &core.Shoot{}}},
+		},
+		Target: extensionswebhook.TargetSeed,
+		ObjectSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{v1beta1constants.LabelExtensionOperatingSystemConfigTypePrefix + osType: "true"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The handler of gardener only allows or denies requests, hence the warnings are added by wrapping it.
+	webhook.Webhook.Handler = WithWarnings(webhook.Webhook.Handler, serializer.NewCodecFactory(mgr.GetScheme()).UniversalDecoder(), validator)
+	return webhook, nil
+}
+
+// WithWarnings returns a handler which adds the warnings of the given Warner for the Shoot of the request to the
+// responses of the given handler which allow the request.
+func WithWarnings(handler admission.Handler, decoder runtime.Decoder, warner Warner) admission.Handler {
+	return &warningHandler{Handler: handler, decoder: decoder, warner: warner}
+}
+
+type warningHandler struct {
+	admission.Handler
+	decoder runtime.Decoder
+	warner  Warner
+}
+
+// Handle handles the given admission request.
+func (h *warningHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	response := h.Handler.Handle(ctx, req)
+	if !response.Allowed || len(req.Object.Raw) == 0 {
+		return response
+	}
+
+	// The objects have already been decoded successfully by the wrapped handler.
+	newShoot := &core.Shoot{}
+	if _, _, err := h.decoder.Decode(req.Object.Raw, nil, newShoot); err != nil {
+		return response
+	}
+	var oldObj client.Object
+	if len(req.OldObject.Raw) != 0 {
+		oldShoot := &core.Shoot{}
+		if _, _, err := h.decoder.Decode(req.OldObject.Raw, nil, oldShoot); err != nil {
+			return response
+		}
+		oldObj = oldShoot
+	}
+
+	response.Warnings = append(response.Warnings, h.warner.Warnings(ctx, newShoot, oldObj)...)
+	return response
+}
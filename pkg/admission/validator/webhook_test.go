@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validator_test
+
+import (
+	"context"
+
+	"github.com/gardener/gardener/pkg/apis/core"
+	gardencoreinstall "github.com/gardener/gardener/pkg/apis/core/install"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/admission/validator"
+)
+
+type fakeWarner struct {
+	newShoot, oldShoot *core.Shoot
+}
+
+func (w *fakeWarner) Warnings(_ context.Context, newObj, oldObj client.Object) []string {
+	w.newShoot = newObj.(*core.Shoot)
+	if oldObj != nil {
+		w.oldShoot = oldObj.(*core.Shoot)
+	}
+	return []string{"foo"}
+}
+
+var _ = Describe("Webhook", func() {
+	Describe("#WithWarnings", func() {
+		var (
+			ctx = context.Background()
+
+			decoder runtime.Decoder
+			warner  *fakeWarner
+			request admission.Request
+		)
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			gardencoreinstall.Install(scheme)
+			decoder = serializer.NewCodecFactory(scheme).UniversalDecoder()
+			warner = &fakeWarner{}
+
+			request = admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"core.gardener.cloud/v1beta1","kind":"Shoot","metadata":{"name":"shoot-1"}}`)},
+				OldObject: runtime.RawExtension{Raw: []byte(`{"apiVersion":"core.gardener.cloud/v1beta1","kind":"Shoot","metadata":{"name":"shoot-0"}}`)},
+			}}
+		})
+
+		It("should add the warnings to allowed requests", func() {
+			handler := validator.WithWarnings(admission.HandlerFunc(func(context.Context, admission.Request) admission.Response {
+				return admission.Allowed("").WithWarnings("bar")
+			}), decoder, warner)
+
+			response := handler.Handle(ctx, request)
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(Equal([]string{"bar", "foo"}))
+			Expect(warner.newShoot.Name).To(Equal("shoot-1"))
+			Expect(warner.oldShoot.Name).To(Equal("shoot-0"))
+		})
+
+		It("should pass no old Shoot on creation", func() {
+			request.Operation = admissionv1.Create
+			request.OldObject = runtime.RawExtension{}
+			handler := validator.WithWarnings(admission.HandlerFunc(func(context.Context, admission.Request) admission.Response {
+				return admission.Allowed("")
+			}), decoder, warner)
+
+			Expect(handler.Handle(ctx, request).Warnings).To(Equal([]string{"foo"}))
+			Expect(warner.oldShoot).To(BeNil())
+		})
+
+		It("should not add the warnings to denied requests", func() {
+			handler := validator.WithWarnings(admission.HandlerFunc(func(context.Context, admission.Request) admission.Response {
+				return admission.Denied("invalid")
+			}), decoder, warner)
+
+			response := handler.Handle(ctx, request)
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Warnings).To(BeEmpty())
+			Expect(warner.newShoot).To(BeNil())
+		})
+	})
+})
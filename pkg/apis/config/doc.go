@@ -0,0 +1,10 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +k8s:deepcopy-gen=package
+// +groupName="suse-chost.os.extensions.config.gardener.cloud"
+
+//go:generate ../../../hack/update-codegen.sh
+
+package config // import "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config/install"
+)
+
+var decoder runtime.Decoder
+
+func init() {
+	scheme := runtime.NewScheme()
+	install.Install(scheme)
+	decoder = serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDecoder()
+}
+
+// LoadFromFile takes a filename and de-serializes the contents into a ControllerConfiguration object.
+func LoadFromFile(filename string) (*config.ControllerConfiguration, error) {
+	data, err := os.ReadFile(filename) // #nosec: G304 -- The file is given by the operator.
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(data)
+}
+
+// Load takes a byte slice and de-serializes the contents into a ControllerConfiguration object.
+func Load(data []byte) (*config.ControllerConfiguration, error) {
+	cfg := &config.ControllerConfiguration{}
+	if len(data) == 0 {
+		return cfg, nil
+	}
+
+	if _, _, err := decoder.Decode(data, &schema.GroupVersionKind{Kind: "ControllerConfiguration"}, cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ControllerConfiguration defines the configuration for the suse-chost extension.
+type ControllerConfiguration struct {
+	metav1.TypeMeta
+
+	// Repositories are zypper repositories which are configured on all nodes before packages are installed.
+	Repositories []Repository
+	// ProvisionScript configures the provision script of the nodes.
+	ProvisionScript *ProvisionScript
+	// UserData configures the size limit and compression of the user data of the nodes.
+	UserData *UserData
+}
+
+// Repository is a zypper repository, e.g. of an internal SUSE RMT or SMT mirror.
+type Repository struct {
+	// Name is the alias of the repository.
+	Name string
+	// URL is the base URL of the repository.
+	URL string
+	// GPGKey is the ASCII armored public GPG key the repository is signed with.
+	GPGKey *string
+	// Priority is the priority of the repository.
+	Priority *int32
+	// CredentialsSecretRef references a Secret with the `username` and `password` for the repository.
+	// Repositories with credentials are not configured by the provision script but by gardener-node-agent, so that the
+	// credentials are not part of the user data.
+	CredentialsSecretRef *corev1.SecretReference
+}
+
+// ProvisionScript configures the provision script of the nodes.
+type ProvisionScript struct {
+	// DisabledFragments are the names of the fragments which are left out of the provision script.
+	DisabledFragments []string
+}
+
+const (
+	// ProvisionScriptFragmentRepositories is the provision script fragment configuring the zypper repositories.
+	ProvisionScriptFragmentRepositories = "repositories"
+	// ProvisionScriptFragmentPackages is the provision script fragment installing the packages.
+	ProvisionScriptFragmentPackages = "packages"
+	// ProvisionScriptFragmentDocker is the provision script fragment removing the conflict of containerd with docker.
+	ProvisionScriptFragmentDocker = "docker"
+	// ProvisionScriptFragmentContainerd is the provision script fragment configuring containerd.
+	ProvisionScriptFragmentContainerd = "containerd"
+	// ProvisionScriptFragmentJournald is the provision script fragment configuring persistent journald storage.
+	ProvisionScriptFragmentJournald = "journald"
+)
+
+// UserData configures the size limit and compression of the user data of the nodes.
+type UserData struct {
+	// MaxSize is the maximum size of the user data, e.g. 16Ki for AWS.
+	MaxSize *resource.Quantity
+	// Compression is the compression applied to user data exceeding MaxSize.
+	Compression *UserDataCompression
+}
+
+// UserDataCompression is a compression of the user data.
+type UserDataCompression string
+
+// UserDataCompressionGzip compresses the user data with gzip.
+const UserDataCompressionGzip UserDataCompression = "gzip"
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ControllerConfiguration defines the configuration for the suse-chost extension.
+type ControllerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Repositories are zypper repositories which are configured on all nodes before packages are installed.
+	// +optional
+	Repositories []Repository `json:"repositories,omitempty"`
+	// ProvisionScript configures the provision script of the nodes.
+	// +optional
+	ProvisionScript *ProvisionScript `json:"provisionScript,omitempty"`
+	// UserData configures the size limit and compression of the user data of the nodes.
+	// +optional
+	UserData *UserData `json:"userData,omitempty"`
+}
+
+// Repository is a zypper repository, e.g. of an internal SUSE RMT or SMT mirror.
+type Repository struct {
+	// Name is the alias of the repository.
+	Name string `json:"name"`
+	// URL is the base URL of the repository.
+	URL string `json:"url"`
+	// GPGKey is the ASCII armored public GPG key the repository is signed with. It is imported before packages are
+	// installed.
+	// +optional
+	GPGKey *string `json:"gpgKey,omitempty"`
+	// Priority is the priority of the repository, lower values mean higher priority. Defaults to zypper's default
+	// priority.
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+	// CredentialsSecretRef references a Secret with the `username` and `password` for the repository.
+	// Repositories with credentials are not configured by the provision script but by gardener-node-agent, so that the
+	// credentials are not part of the user data.
+	// +optional
+	CredentialsSecretRef *corev1.SecretReference `json:"credentialsSecretRef,omitempty"`
+}
+
+// ProvisionScript configures the provision script of the nodes.
+type ProvisionScript struct {
+	// DisabledFragments are the names of the fragments which are left out of the provision script, one of
+	// `repositories`, `packages`, `docker`, `containerd` and `journald`. The corresponding units and files are not
+	// reconciled by gardener-node-agent either.
+	// +optional
+	DisabledFragments []string `json:"disabledFragments,omitempty"`
+}
+
+// UserData configures the size limit and compression of the user data of the nodes.
+type UserData struct {
+	// MaxSize is the maximum size of the user data, e.g. 16Ki for AWS. The reconciliation of OperatingSystemConfigs
+	// whose user data exceeds it fails with an error naming the largest files, instead of the machine creation failing
+	// later on. Defaults to no limit.
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// Compression is the compression applied to user data exceeding MaxSize, only `gzip` is supported. Only set it if
+	// cloud-init of all images supports compressed user data. The user data of memoryone-chost is never compressed, as
+	// its vSMP configuration must stay readable for the MemoryOne hypervisor.
+	// +optional
+	Compression *UserDataCompression `json:"compression,omitempty"`
+}
+
+// UserDataCompression is a compression of the user data.
+type UserDataCompression string
+
+// UserDataCompressionGzip compresses the user data with gzip.
+const UserDataCompressionGzip UserDataCompression = "gzip"
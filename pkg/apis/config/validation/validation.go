@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
+	susechostvalidation "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost/validation"
+)
+
+// ValidateControllerConfiguration validates the controller configuration of the suse-chost extension.
+func ValidateControllerConfiguration(cfg *config.ControllerConfiguration) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	repositoryNames := sets.New[string]()
+	for i, repository := range cfg.Repositories {
+		idxPath := field.NewPath("repositories").Index(i)
+
+		allErrs = append(allErrs, susechostvalidation.ValidateRepository(repository.Name, repository.URL, repository.GPGKey, repository.Priority, idxPath)...)
+		if repositoryNames.Has(repository.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), repository.Name))
+		}
+		repositoryNames.Insert(repository.Name)
+
+		if ref := repository.CredentialsSecretRef; ref != nil {
+			if len(ref.Name) == 0 {
+				allErrs = append(allErrs, field.Required(idxPath.Child("credentialsSecretRef", "name"), "secret name must not be empty"))
+			}
+			if len(ref.Namespace) == 0 {
+				allErrs = append(allErrs, field.Required(idxPath.Child("credentialsSecretRef", "namespace"), "secret namespace must not be empty"))
+			}
+		}
+	}
+
+	if cfg.ProvisionScript != nil {
+		allErrs = append(allErrs, validateProvisionScript(cfg.ProvisionScript, field.NewPath("provisionScript"))...)
+	}
+
+	if cfg.UserData != nil {
+		allErrs = append(allErrs, validateUserData(cfg.UserData, field.NewPath("userData"))...)
+	}
+
+	return allErrs
+}
+
+// disableableProvisionScriptFragments are the provision script fragments which may be disabled. All other fragments
+// are required for joining the node to the cluster.
+var disableableProvisionScriptFragments = sets.New(
+	config.ProvisionScriptFragmentRepositories,
+	config.ProvisionScriptFragmentPackages,
+	config.ProvisionScriptFragmentDocker,
+	config.ProvisionScriptFragmentContainerd,
+	config.ProvisionScriptFragmentJournald,
+)
+
+func validateProvisionScript(provisionScript *config.ProvisionScript, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	disabledFragments := sets.New[string]()
+	for i, name := range provisionScript.DisabledFragments {
+		idxPath := fldPath.Child("disabledFragments").Index(i)
+
+		if !disableableProvisionScriptFragments.Has(name) {
+			allErrs = append(allErrs, field.NotSupported(idxPath, name, sets.List(disableableProvisionScriptFragments)))
+		} else if disabledFragments.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, name))
+		}
+		disabledFragments.Insert(name)
+	}
+
+	return allErrs
+}
+
+var supportedUserDataCompressions = sets.New(string(config.UserDataCompressionGzip))
+
+func validateUserData(userData *config.UserData, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if userData.MaxSize != nil && userData.MaxSize.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxSize"), userData.MaxSize.String(), "must be greater than 0"))
+	}
+
+	if userData.Compression != nil {
+		if !supportedUserDataCompressions.Has(string(*userData.Compression)) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("compression"), *userData.Compression, sets.List(supportedUserDataCompressions)))
+		}
+		if userData.MaxSize == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("maxSize"), "must be set if the user data is compressed"))
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
+	. "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config/validation"
+)
+
+var _ = Describe("Validation", func() {
+	var cfg *config.ControllerConfiguration
+
+	BeforeEach(func() {
+		cfg = &config.ControllerConfiguration{
+			Repositories: []config.Repository{{
+				Name:                 "rmt",
+				URL:                  "https://rmt.example.com/repo/SUSE/Products/SLE-Product-SLES/15-SP5/x86_64/product",
+				Priority:             ptr.To[int32](90),
+				CredentialsSecretRef: &corev1.SecretReference{Namespace: "garden", Name: "rmt-credentials"},
+			}},
+		}
+	})
+
+	Describe("#ValidateControllerConfiguration", func() {
+		It("should allow an empty configuration", func() {
+			Expect(ValidateControllerConfiguration(&config.ControllerConfiguration{})).To(BeEmpty())
+		})
+
+		It("should allow a valid configuration", func() {
+			Expect(ValidateControllerConfiguration(cfg)).To(BeEmpty())
+		})
+
+		It("should forbid invalid repositories", func() {
+			cfg.Repositories[0].URL = "gopher://rmt.example.com"
+
+			Expect(ValidateControllerConfiguration(cfg)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("repositories[0].url"),
+				})),
+			))
+		})
+
+		It("should forbid duplicate repository names", func() {
+			cfg.Repositories = append(cfg.Repositories, config.Repository{Name: "rmt", URL: "https://rmt.example.com/other"})
+
+			Expect(ValidateControllerConfiguration(cfg)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("repositories[1].name"),
+				})),
+			))
+		})
+
+		It("should allow disabling optional provision script fragments", func() {
+			cfg.ProvisionScript = &config.ProvisionScript{DisabledFragments: []string{"docker", "packages"}}
+
+			Expect(ValidateControllerConfiguration(cfg)).To(BeEmpty())
+		})
+
+		It("should forbid disabling unknown or required provision script fragments", func() {
+			cfg.ProvisionScript = &config.ProvisionScript{DisabledFragments: []string{"docker", "units", "docker"}}
+
+			Expect(ValidateControllerConfiguration(cfg)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("provisionScript.disabledFragments[1]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("provisionScript.disabledFragments[2]"),
+				})),
+			))
+		})
+
+		It("should allow a user data size limit with compression", func() {
+			cfg.UserData = &config.UserData{MaxSize: ptr.To(resource.MustParse("16Ki")), Compression: ptr.To(config.UserDataCompressionGzip)}
+
+			Expect(ValidateControllerConfiguration(cfg)).To(BeEmpty())
+		})
+
+		It("should forbid invalid user data size limits and compressions", func() {
+			cfg.UserData = &config.UserData{MaxSize: ptr.To(resource.MustParse("0")), Compression: ptr.To(config.UserDataCompression("zstd"))}
+
+			Expect(ValidateControllerConfiguration(cfg)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("userData.maxSize"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("userData.compression"),
+				})),
+			))
+		})
+
+		It("should require a user data size limit for compression", func() {
+			cfg.UserData = &config.UserData{Compression: ptr.To(config.UserDataCompressionGzip)}
+
+			Expect(ValidateControllerConfiguration(cfg)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("userData.maxSize"),
+				})),
+			))
+		})
+
+		It("should require the name and namespace of the credentials secret", func() {
+			cfg.Repositories[0].CredentialsSecretRef = &corev1.SecretReference{}
+
+			Expect(ValidateControllerConfiguration(cfg)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("repositories[0].credentialsSecretRef.name"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("repositories[0].credentialsSecretRef.namespace"),
+				})),
+			))
+		})
+	})
+})
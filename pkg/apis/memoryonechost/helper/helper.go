@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost"
+)
+
+const (
+	// VsmpKeyMemoryTopology is the vSMP parameter configured by the deprecated `memoryTopology` field.
+	VsmpKeyMemoryTopology = "mem_topology"
+	// VsmpKeySystemMemory is the vSMP parameter configured by the deprecated `systemMemory` field.
+	VsmpKeySystemMemory = "system_memory"
+)
+
+// VsmpConfiguration returns the effective vSMP parameters of the given configuration, the deprecated fields are parsed
+// with ParseLegacyValue. The parameters of `vsmpConfiguration` are overridden by the ones of the deprecated
+// `systemMemory` field, which are in turn overridden by the ones of the deprecated `memoryTopology` field. Defaults are
+// not applied.
+// It also returns a warning for each parameter overridden with a different value and for each part of the deprecated
+// fields which cannot be parsed and is ignored.
+func VsmpConfiguration(config *memoryonechost.OperatingSystemConfiguration) (map[string]string, []string) {
+	vsmpConfiguration := map[string]string{}
+	if config == nil {
+		return vsmpConfiguration, nil
+	}
+
+	// Always work on a copy, the given configuration must not be mutated.
+	maps.Copy(vsmpConfiguration, config.VsmpConfiguration)
+
+	var warnings []string
+	for _, legacy := range []struct {
+		fieldName string
+		key       string
+		value     *string
+	}{
+		{"systemMemory", VsmpKeySystemMemory, config.SystemMemory},
+		{"memoryTopology", VsmpKeyMemoryTopology, config.MemoryTopology},
+	} {
+		if legacy.value == nil {
+			continue
+		}
+
+		parameters, parseWarnings := ParseLegacyValue(legacy.key, *legacy.value)
+		for _, warning := range parseWarnings {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", legacy.fieldName, warning))
+		}
+
+		for _, parameter := range parameters {
+			if value, ok := vsmpConfiguration[parameter.Key]; ok && value != parameter.Value {
+				warnings = append(warnings, fmt.Sprintf("%s: overrides %s=%q with %q", legacy.fieldName, parameter.Key, value, parameter.Value))
+			}
+			vsmpConfiguration[parameter.Key] = parameter.Value
+		}
+	}
+
+	return vsmpConfiguration, warnings
+}
+
+// VsmpCatalogWarnings checks the effective vSMP parameters of the given configuration (see VsmpConfiguration) against
+// the catalog of known parameters, except for the experimental ones. It returns a warning for each unknown parameter,
+// for each value out of the range of its parameter, and for each malformed value. Validation only rejects malformed
+// values of `vsmpConfiguration`, but not the ones of the deprecated fields.
+func VsmpCatalogWarnings(config *memoryonechost.OperatingSystemConfiguration) []string {
+	var (
+		warnings             []string
+		vsmpConfiguration, _ = VsmpConfiguration(config)
+	)
+
+	for _, key := range slices.Sorted(maps.Keys(vsmpConfiguration)) {
+		if config != nil && slices.Contains(config.ExperimentalVsmpParameters, key) {
+			continue
+		}
+
+		value := vsmpConfiguration[key]
+		definition, ok := LookupVsmpParameter(key)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("unknown parameter %s, it is passed to the hypervisor anyway", key))
+			continue
+		}
+		if err := definition.ValidateValue(value); err != nil {
+			warnings = append(warnings, fmt.Sprintf("malformed parameter %s=%q: %v", key, value, err))
+			continue
+		}
+		if err := definition.ValidateRange(value); err != nil {
+			warnings = append(warnings, fmt.Sprintf("parameter %s=%q: %v, it is passed to the hypervisor anyway", key, value, err))
+		}
+	}
+
+	return warnings
+}
+
+// VsmpParameter is a key-value pair of the vSMP configuration.
+type VsmpParameter struct {
+	Key   string
+	Value string
+}
+
+// ParseLegacyValue parses the value of a deprecated field configuring the vSMP parameter with the given key, e.g. of
+// `memoryTopology` for `mem_topology`. For backwards compatibility, the value may inject further parameters separated
+// by ';', e.g. `3;debug_features=&0xff`. Everything after the first line break is dropped.
+// The injected parameters are returned first, followed by the parameter of the field, so that the latter takes
+// precedence. A warning is returned for each injected part which is not a key-value pair.
+func ParseLegacyValue(key, value string) ([]VsmpParameter, []string) {
+	if i := strings.IndexFunc(value, func(r rune) bool { return unicode.IsControl(r) && r != '\t' }); i >= 0 {
+		value = value[:i]
+	}
+
+	segments := strings.Split(value, ";")
+	if len(segments) == 1 {
+		// The value is taken as is if nothing is injected, so that the rendered configuration does not change.
+		return []VsmpParameter{{Key: key, Value: value}}, nil
+	}
+
+	var (
+		parameters []VsmpParameter
+		warnings   []string
+	)
+	for _, segment := range segments[1:] {
+		segment = strings.TrimSpace(segment)
+		if len(segment) == 0 {
+			continue
+		}
+
+		injectedKey, injectedValue, found := strings.Cut(segment, "=")
+		injectedKey, injectedValue = strings.TrimSpace(injectedKey), strings.TrimSpace(injectedValue)
+		if !found || len(injectedKey) == 0 {
+			warnings = append(warnings, fmt.Sprintf("ignoring %q, which is not a key-value pair", segment))
+			continue
+		}
+		parameters = append(parameters, VsmpParameter{Key: injectedKey, Value: injectedValue})
+	}
+
+	if own := strings.TrimSpace(segments[0]); len(own) > 0 {
+		parameters = append(parameters, VsmpParameter{Key: key, Value: own})
+	} else {
+		warnings = append(warnings, fmt.Sprintf("ignoring the empty value for %s", key))
+	}
+
+	return parameters, warnings
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helper_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost"
+	. "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/helper"
+)
+
+var _ = Describe("Helper", func() {
+	Describe("#VsmpConfiguration", func() {
+		It("should return no parameters for a nil configuration", func() {
+			Expect(VsmpConfiguration(nil)).To(BeEmpty())
+		})
+
+		It("should return the parameters of vsmpConfiguration", func() {
+			vsmpConfiguration, warnings := VsmpConfiguration(&memoryonechost.OperatingSystemConfiguration{
+				VsmpConfiguration: map[string]string{"mem_topology": "3", "debug_features": "&0xff"},
+			})
+
+			Expect(vsmpConfiguration).To(Equal(map[string]string{"mem_topology": "3", "debug_features": "&0xff"}))
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should merge the parameters of the legacy fields", func() {
+			vsmpConfiguration, warnings := VsmpConfiguration(&memoryonechost.OperatingSystemConfiguration{
+				MemoryTopology:    ptr.To("3;debug_features=&0xff"),
+				SystemMemory:      ptr.To("7x"),
+				VsmpConfiguration: map[string]string{"pci_dev_filter": `"00:0a:ce"`},
+			})
+
+			Expect(vsmpConfiguration).To(Equal(map[string]string{"mem_topology": "3", "system_memory": "7x", "debug_features": "&0xff", "pci_dev_filter": `"00:0a:ce"`}))
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should let memoryTopology override systemMemory and both override vsmpConfiguration", func() {
+			vsmpConfiguration, warnings := VsmpConfiguration(&memoryonechost.OperatingSystemConfiguration{
+				MemoryTopology:    ptr.To("4; debug_features=&0x0f"),
+				SystemMemory:      ptr.To("8x;debug_features=&0xf0"),
+				VsmpConfiguration: map[string]string{"mem_topology": "3", "system_memory": "7x", "debug_features": "&0xff"},
+			})
+
+			Expect(vsmpConfiguration).To(Equal(map[string]string{"mem_topology": "4", "system_memory": "8x", "debug_features": "&0x0f"}))
+			Expect(warnings).To(Equal([]string{
+				`systemMemory: overrides debug_features="&0xff" with "&0xf0"`,
+				`systemMemory: overrides system_memory="7x" with "8x"`,
+				`memoryTopology: overrides debug_features="&0xf0" with "&0x0f"`,
+				`memoryTopology: overrides mem_topology="3" with "4"`,
+			}))
+		})
+
+		It("should not warn about legacy fields setting the same value", func() {
+			_, warnings := VsmpConfiguration(&memoryonechost.OperatingSystemConfiguration{
+				MemoryTopology:    ptr.To("3"),
+				VsmpConfiguration: map[string]string{"mem_topology": "3"},
+			})
+
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should not mutate the given configuration", func() {
+			config := &memoryonechost.OperatingSystemConfiguration{
+				MemoryTopology:    ptr.To("3;foo=baz"),
+				VsmpConfiguration: map[string]string{"foo": "bar"},
+			}
+			original := config.DeepCopy()
+
+			VsmpConfiguration(config)
+			Expect(config).To(Equal(original))
+		})
+	})
+
+	DescribeTable("#ParseLegacyValue",
+		func(value string, expectedParameters []VsmpParameter, expectedWarnings []string) {
+			parameters, warnings := ParseLegacyValue("mem_topology", value)
+
+			Expect(parameters).To(Equal(expectedParameters))
+			Expect(warnings).To(Equal(expectedWarnings))
+		},
+		Entry("plain value", "3", []VsmpParameter{{Key: "mem_topology", Value: "3"}}, nil),
+		Entry("plain value with whitespace", " 3 ", []VsmpParameter{{Key: "mem_topology", Value: " 3 "}}, nil),
+		Entry("injected parameters", "3; debug_features = &0xff;foo=bar;", []VsmpParameter{
+			{Key: "debug_features", Value: "&0xff"},
+			{Key: "foo", Value: "bar"},
+			{Key: "mem_topology", Value: "3"},
+		}, nil),
+		Entry("injected value containing '='", "3;pci_dev_filter=a=b", []VsmpParameter{{Key: "pci_dev_filter", Value: "a=b"}, {Key: "mem_topology", Value: "3"}}, nil),
+		Entry("line break", "3;foo=bar\nsystem_memory=1x", []VsmpParameter{{Key: "foo", Value: "bar"}, {Key: "mem_topology", Value: "3"}}, nil),
+		Entry("injected parts without key", "3;foo;=bar", []VsmpParameter{{Key: "mem_topology", Value: "3"}}, []string{
+			`ignoring "foo", which is not a key-value pair`,
+			`ignoring "=bar", which is not a key-value pair`,
+		}),
+		Entry("empty own value", ";foo=bar", []VsmpParameter{{Key: "foo", Value: "bar"}}, []string{"ignoring the empty value for mem_topology"}),
+	)
+
+	Describe("#VsmpCatalogWarnings", func() {
+		It("should not warn about known parameters", func() {
+			Expect(VsmpCatalogWarnings(&memoryonechost.OperatingSystemConfiguration{
+				MemoryTopology:    ptr.To("3"),
+				VsmpConfiguration: map[string]string{"system_memory": "7x", "pci_dev_filter": `"00:0a:ce"`},
+			})).To(BeEmpty())
+		})
+
+		It("should warn about unknown parameters which are not experimental", func() {
+			Expect(VsmpCatalogWarnings(&memoryonechost.OperatingSystemConfiguration{
+				VsmpConfiguration:          map[string]string{"mem_topolgy": "3", "foo": "bar"},
+				ExperimentalVsmpParameters: []string{"foo"},
+			})).To(Equal([]string{`unknown parameter mem_topolgy, it is passed to the hypervisor anyway`}))
+		})
+
+		It("should warn about unknown or malformed parameters of the legacy fields", func() {
+			Expect(VsmpCatalogWarnings(&memoryonechost.OperatingSystemConfiguration{
+				MemoryTopology:             ptr.To("3;mem_topolgy=4;debug_features=0xff;foo=bar"),
+				SystemMemory:               ptr.To("100"),
+				ExperimentalVsmpParameters: []string{"foo"},
+			})).To(Equal([]string{
+				`malformed parameter debug_features="0xff": value must be a hex mask of at most 64 bits, e.g. &0xff`,
+				`unknown parameter mem_topolgy, it is passed to the hypervisor anyway`,
+				`malformed parameter system_memory="100": value must be a multiplier, e.g. 6x`,
+			}))
+		})
+
+		It("should warn about values out of range which are not experimental", func() {
+			Expect(VsmpCatalogWarnings(&memoryonechost.OperatingSystemConfiguration{
+				SystemMemory:               ptr.To("17x"),
+				VsmpConfiguration:          map[string]string{"mem_topology": "0"},
+				ExperimentalVsmpParameters: []string{"system_memory"},
+			})).To(Equal([]string{`parameter mem_topology="0": value must be between 1 and 8, it is passed to the hypervisor anyway`}))
+		})
+	})
+})
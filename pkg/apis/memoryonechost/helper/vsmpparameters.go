@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// VsmpParameterType is the type of the value of a vSMP parameter.
+type VsmpParameterType string
+
+const (
+	// VsmpParameterTypeInteger is a decimal integer, e.g. `2`.
+	VsmpParameterTypeInteger VsmpParameterType = "integer"
+	// VsmpParameterTypeMultiplier is a decimal integer followed by `x`, e.g. `6x`.
+	VsmpParameterTypeMultiplier VsmpParameterType = "multiplier"
+	// VsmpParameterTypeHexMask is a hexadecimal bit mask of at most 64 bits prefixed with `&0x`, e.g. `&0xff`.
+	VsmpParameterTypeHexMask VsmpParameterType = "hexMask"
+	// VsmpParameterTypeString is a string in double quotes, quotes inside of it are escaped, e.g. `"00:0a:ce"`.
+	VsmpParameterTypeString VsmpParameterType = "string"
+)
+
+// VsmpParameterDefinition is the definition of a known vSMP parameter in the catalog.
+type VsmpParameterDefinition struct {
+	// Key is the key of the parameter, e.g. `mem_topology`.
+	Key string `json:"key"`
+	// Type is the type of the value of the parameter.
+	Type VsmpParameterType `json:"type"`
+	// Min is the smallest supported value of `integer` and `multiplier` parameters.
+	Min *int64 `json:"min,omitempty"`
+	// Max is the largest supported value of `integer` and `multiplier` parameters.
+	Max *int64 `json:"max,omitempty"`
+	// Description describes the parameter.
+	Description string `json:"description"`
+}
+
+var (
+	//go:embed vsmpparameters.yaml
+	vsmpParametersYAML []byte
+
+	vsmpParameters = mustLoadVsmpParameters(vsmpParametersYAML)
+
+	vsmpIntegerRegex    = regexp.MustCompile(`^[0-9]+$`)
+	vsmpMultiplierRegex = regexp.MustCompile(`^([0-9]+)x$`)
+	vsmpHexMaskRegex    = regexp.MustCompile(`^&0x[0-9a-fA-F]{1,16}$`)
+	vsmpStringRegex     = regexp.MustCompile(`^"([^"\\]|\\.)*"$`)
+)
+
+// LookupVsmpParameter returns the definition of the vSMP parameter with the given key from the catalog of known
+// parameters.
+func LookupVsmpParameter(key string) (VsmpParameterDefinition, bool) {
+	i := slices.IndexFunc(vsmpParameters, func(definition VsmpParameterDefinition) bool { return definition.Key == key })
+	if i < 0 {
+		return VsmpParameterDefinition{}, false
+	}
+	return vsmpParameters[i], true
+}
+
+// ValidateValue returns an error if the given value does not match the type of the parameter.
+func (d VsmpParameterDefinition) ValidateValue(value string) error {
+	switch d.Type {
+	case VsmpParameterTypeInteger:
+		if !vsmpIntegerRegex.MatchString(value) {
+			return errors.New("value must be an integer, e.g. 2")
+		}
+		return validateNumber(value)
+	case VsmpParameterTypeMultiplier:
+		match := vsmpMultiplierRegex.FindStringSubmatch(value)
+		if match == nil {
+			return errors.New("value must be a multiplier, e.g. 6x")
+		}
+		return validateNumber(match[1])
+	case VsmpParameterTypeHexMask:
+		if !vsmpHexMaskRegex.MatchString(value) {
+			return errors.New("value must be a hex mask of at most 64 bits, e.g. &0xff")
+		}
+	case VsmpParameterTypeString:
+		if !vsmpStringRegex.MatchString(value) {
+			return errors.New(`value must be a string in double quotes with escaped quotes inside, e.g. "00:0a:ce"`)
+		}
+	default:
+		return fmt.Errorf("unknown type %q of vSMP parameter %s", d.Type, d.Key)
+	}
+
+	return nil
+}
+
+// ValidateRange returns an error if the given value, which must be valid according to ValidateValue, is out of the
+// range of the parameter.
+func (d VsmpParameterDefinition) ValidateRange(value string) error {
+	number, suffix := value, ""
+	switch d.Type {
+	case VsmpParameterTypeInteger:
+	case VsmpParameterTypeMultiplier:
+		number, suffix = strings.TrimSuffix(value, "x"), "x"
+	default:
+		return nil
+	}
+
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return fmt.Errorf("value is out of range: %w", err)
+	}
+
+	switch {
+	case d.Min != nil && d.Max != nil && (n < *d.Min || n > *d.Max):
+		return fmt.Errorf("value must be between %d%s and %d%s", *d.Min, suffix, *d.Max, suffix)
+	case d.Min != nil && n < *d.Min:
+		return fmt.Errorf("value must be at least %d%s", *d.Min, suffix)
+	case d.Max != nil && n > *d.Max:
+		return fmt.Errorf("value must be at most %d%s", *d.Max, suffix)
+	}
+
+	return nil
+}
+
+// validateNumber returns an error if the given decimal number does not fit into 64 bits.
+func validateNumber(number string) error {
+	if _, err := strconv.ParseInt(number, 10, 64); err != nil {
+		return fmt.Errorf("value is out of range: %w", err)
+	}
+	return nil
+}
+
+// mustLoadVsmpParameters loads the given catalog of vSMP parameters. It panics for an invalid catalog, as it is
+// embedded into the binary.
+func mustLoadVsmpParameters(data []byte) []VsmpParameterDefinition {
+	var definitions []VsmpParameterDefinition
+	if err := yaml.UnmarshalStrict(data, &definitions); err != nil {
+		panic(fmt.Errorf("failed to load the catalog of vSMP parameters: %w", err))
+	}
+
+	keys := make(map[string]struct{}, len(definitions))
+	for _, definition := range definitions {
+		if _, ok := keys[definition.Key]; ok || len(definition.Key) == 0 {
+			panic(fmt.Errorf("invalid key %q in the catalog of vSMP parameters", definition.Key))
+		}
+		keys[definition.Key] = struct{}{}
+
+		if !slices.Contains([]VsmpParameterType{VsmpParameterTypeInteger, VsmpParameterTypeMultiplier, VsmpParameterTypeHexMask, VsmpParameterTypeString}, definition.Type) {
+			panic(fmt.Errorf("unknown type %q of vSMP parameter %s in the catalog", definition.Type, definition.Key))
+		}
+		if (definition.Min != nil || definition.Max != nil) && definition.Type != VsmpParameterTypeInteger && definition.Type != VsmpParameterTypeMultiplier {
+			panic(fmt.Errorf("vSMP parameter %s of type %q in the catalog must not have a range", definition.Key, definition.Type))
+		}
+		if definition.Min != nil && definition.Max != nil && *definition.Min > *definition.Max {
+			panic(fmt.Errorf("invalid range of vSMP parameter %s in the catalog", definition.Key))
+		}
+	}
+
+	return definitions
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helper_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	. "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/helper"
+)
+
+var _ = Describe("VsmpParameters", func() {
+	Describe("#LookupVsmpParameter", func() {
+		It("should return the definitions of the parameters configured by the typed fields", func() {
+			definition, ok := LookupVsmpParameter(VsmpKeyMemoryTopology)
+			Expect(ok).To(BeTrue())
+			Expect(definition).To(Equal(VsmpParameterDefinition{
+				Key:         "mem_topology",
+				Type:        VsmpParameterTypeInteger,
+				Min:         ptr.To[int64](1),
+				Max:         ptr.To[int64](8),
+				Description: "Number of memory topology nodes presented to the operating system.",
+			}))
+
+			definition, ok = LookupVsmpParameter(VsmpKeySystemMemory)
+			Expect(ok).To(BeTrue())
+			Expect(definition.Type).To(Equal(VsmpParameterTypeMultiplier))
+			Expect(definition.Min).To(Equal(ptr.To[int64](1)))
+			Expect(definition.Max).To(Equal(ptr.To[int64](16)))
+		})
+
+		It("should not find unknown parameters", func() {
+			_, ok := LookupVsmpParameter("mem_topolgy")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("#ValidateValue", func() {
+		DescribeTable("should accept valid values",
+			func(parameterType VsmpParameterType, value string) {
+				definition := VsmpParameterDefinition{Key: "foo", Type: parameterType}
+				Expect(definition.ValidateValue(value)).To(Succeed())
+			},
+			Entry("integer", VsmpParameterTypeInteger, "16"),
+			Entry("multiplier", VsmpParameterTypeMultiplier, "1x"),
+			Entry("hex mask", VsmpParameterTypeHexMask, "&0xFFffFFffFFffFFff"),
+			Entry("string", VsmpParameterTypeString, `"00:0a:ce"`),
+			Entry("string with escaped quotes", VsmpParameterTypeString, `"foo \"bar\""`),
+			Entry("empty string", VsmpParameterTypeString, `""`),
+		)
+
+		DescribeTable("should reject malformed values",
+			func(definition VsmpParameterDefinition, value, message string) {
+				Expect(definition.ValidateValue(value)).To(MatchError(message))
+			},
+			Entry("negative integer", VsmpParameterDefinition{Type: VsmpParameterTypeInteger}, "-1", "value must be an integer, e.g. 2"),
+			Entry("integer too large", VsmpParameterDefinition{Type: VsmpParameterTypeInteger}, "99999999999999999999", `value is out of range: strconv.ParseInt: parsing "99999999999999999999": value out of range`),
+			Entry("multiplier too large", VsmpParameterDefinition{Type: VsmpParameterTypeMultiplier}, "99999999999999999999x", `value is out of range: strconv.ParseInt: parsing "99999999999999999999": value out of range`),
+			Entry("multiplier with upper-case X", VsmpParameterDefinition{Type: VsmpParameterTypeMultiplier}, "6X", "value must be a multiplier, e.g. 6x"),
+			Entry("hex mask without digits", VsmpParameterDefinition{Type: VsmpParameterTypeHexMask}, "&0x", "value must be a hex mask of at most 64 bits, e.g. &0xff"),
+			Entry("string with trailing backslash", VsmpParameterDefinition{Type: VsmpParameterTypeString}, `"foo\"`, `value must be a string in double quotes with escaped quotes inside, e.g. "00:0a:ce"`),
+			Entry("unknown type", VsmpParameterDefinition{Key: "foo", Type: "boolean"}, "true", `unknown type "boolean" of vSMP parameter foo`),
+		)
+	})
+
+	Describe("#ValidateRange", func() {
+		DescribeTable("should accept values in range",
+			func(definition VsmpParameterDefinition, value string) {
+				Expect(definition.ValidateRange(value)).To(Succeed())
+			},
+			Entry("integer at the minimum", VsmpParameterDefinition{Type: VsmpParameterTypeInteger, Min: ptr.To[int64](1), Max: ptr.To[int64](8)}, "1"),
+			Entry("multiplier at the maximum", VsmpParameterDefinition{Type: VsmpParameterTypeMultiplier, Min: ptr.To[int64](1), Max: ptr.To[int64](16)}, "16x"),
+			Entry("integer without range", VsmpParameterDefinition{Type: VsmpParameterTypeInteger}, "1000"),
+			Entry("hex mask", VsmpParameterDefinition{Type: VsmpParameterTypeHexMask}, "&0xff"),
+		)
+
+		DescribeTable("should reject values out of range",
+			func(definition VsmpParameterDefinition, value, message string) {
+				Expect(definition.ValidateRange(value)).To(MatchError(message))
+			},
+			Entry("integer below the range", VsmpParameterDefinition{Type: VsmpParameterTypeInteger, Min: ptr.To[int64](1), Max: ptr.To[int64](8)}, "0", "value must be between 1 and 8"),
+			Entry("multiplier above the range", VsmpParameterDefinition{Type: VsmpParameterTypeMultiplier, Min: ptr.To[int64](1), Max: ptr.To[int64](16)}, "17x", "value must be between 1x and 16x"),
+			Entry("integer below the minimum", VsmpParameterDefinition{Type: VsmpParameterTypeInteger, Min: ptr.To[int64](2)}, "1", "value must be at least 2"),
+			Entry("multiplier above the maximum", VsmpParameterDefinition{Type: VsmpParameterTypeMultiplier, Max: ptr.To[int64](8)}, "9x", "value must be at most 8x"),
+		)
+	})
+})
@@ -20,4 +20,53 @@ type OperatingSystemConfiguration struct {
 	SystemMemory *string
 	// VsmpConfiguration allows to configure any setting of vSMP
 	VsmpConfiguration map[string]string
+	// ExperimentalVsmpParameters are keys of `vsmpConfiguration` which are passed to vSMP without being checked against
+	// the catalog of known parameters.
+	ExperimentalVsmpParameters []string
+	// UserDataFormat is the format of the part of the user data provisioning the nodes.
+	UserDataFormat *UserDataFormat
+	// AdditionalParts are parts which are added to the user data between the vSMP configuration and the part
+	// provisioning the nodes, in the given order.
+	AdditionalParts []UserDataPart
+	// Packages allows to configure the packages installed on the nodes during provisioning on top of the default
+	// packages.
+	Packages *Packages
 }
+
+// Packages allows to add and remove packages installed on the nodes during provisioning.
+type Packages struct {
+	// Add is a list of packages which are installed in addition to the default packages.
+	Add []string
+	// Remove is a list of default packages which are not installed.
+	Remove []string
+	// InstallTimeout is the time after which the provisioning gives up installing the packages.
+	InstallTimeout *metav1.Duration
+}
+
+// UserDataPart is an additional part of the user data.
+type UserDataPart struct {
+	// ContentType is the content type of the part.
+	ContentType string
+	// Content is the content of the part.
+	Content *string
+	// SecretRef references a Secret holding the content of the part.
+	SecretRef *UserDataPartSecretRef
+}
+
+// UserDataPartSecretRef references the content of an additional part of the user data in a Secret.
+type UserDataPartSecretRef struct {
+	// ResourceName is the name of a resource in the Shoot's `spec.resources` which references the Secret.
+	ResourceName string
+	// DataKey is the key in the data of the Secret holding the content.
+	DataKey string
+}
+
+// UserDataFormat is the format of the user data of the nodes.
+type UserDataFormat string
+
+const (
+	// UserDataFormatScript renders the user data as a bash script.
+	UserDataFormatScript UserDataFormat = "script"
+	// UserDataFormatCloudConfig renders the user data as cloud-config.
+	UserDataFormatCloudConfig UserDataFormat = "cloud-config"
+)
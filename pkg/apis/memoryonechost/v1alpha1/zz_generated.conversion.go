@@ -13,6 +13,7 @@ import (
 	unsafe "unsafe"
 
 	memoryonechost "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -34,6 +35,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*Packages)(nil), (*memoryonechost.Packages)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_Packages_To_memoryonechost_Packages(a.(*Packages), b.(*memoryonechost.Packages), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*memoryonechost.Packages)(nil), (*Packages)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_memoryonechost_Packages_To_v1alpha1_Packages(a.(*memoryonechost.Packages), b.(*Packages), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*UserDataPart)(nil), (*memoryonechost.UserDataPart)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_UserDataPart_To_memoryonechost_UserDataPart(a.(*UserDataPart), b.(*memoryonechost.UserDataPart), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*memoryonechost.UserDataPart)(nil), (*UserDataPart)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_memoryonechost_UserDataPart_To_v1alpha1_UserDataPart(a.(*memoryonechost.UserDataPart), b.(*UserDataPart), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*UserDataPartSecretRef)(nil), (*memoryonechost.UserDataPartSecretRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_UserDataPartSecretRef_To_memoryonechost_UserDataPartSecretRef(a.(*UserDataPartSecretRef), b.(*memoryonechost.UserDataPartSecretRef), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*memoryonechost.UserDataPartSecretRef)(nil), (*UserDataPartSecretRef)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_memoryonechost_UserDataPartSecretRef_To_v1alpha1_UserDataPartSecretRef(a.(*memoryonechost.UserDataPartSecretRef), b.(*UserDataPartSecretRef), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -41,6 +72,10 @@ func autoConvert_v1alpha1_OperatingSystemConfiguration_To_memoryonechost_Operati
 	out.MemoryTopology = (*string)(unsafe.Pointer(in.MemoryTopology))
 	out.SystemMemory = (*string)(unsafe.Pointer(in.SystemMemory))
 	out.VsmpConfiguration = *(*map[string]string)(unsafe.Pointer(&in.VsmpConfiguration))
+	out.ExperimentalVsmpParameters = *(*[]string)(unsafe.Pointer(&in.ExperimentalVsmpParameters))
+	out.UserDataFormat = (*memoryonechost.UserDataFormat)(unsafe.Pointer(in.UserDataFormat))
+	out.AdditionalParts = *(*[]memoryonechost.UserDataPart)(unsafe.Pointer(&in.AdditionalParts))
+	out.Packages = (*memoryonechost.Packages)(unsafe.Pointer(in.Packages))
 	return nil
 }
 
@@ -53,6 +88,10 @@ func autoConvert_memoryonechost_OperatingSystemConfiguration_To_v1alpha1_Operati
 	out.MemoryTopology = (*string)(unsafe.Pointer(in.MemoryTopology))
 	out.SystemMemory = (*string)(unsafe.Pointer(in.SystemMemory))
 	out.VsmpConfiguration = *(*map[string]string)(unsafe.Pointer(&in.VsmpConfiguration))
+	out.ExperimentalVsmpParameters = *(*[]string)(unsafe.Pointer(&in.ExperimentalVsmpParameters))
+	out.UserDataFormat = (*UserDataFormat)(unsafe.Pointer(in.UserDataFormat))
+	out.AdditionalParts = *(*[]UserDataPart)(unsafe.Pointer(&in.AdditionalParts))
+	out.Packages = (*Packages)(unsafe.Pointer(in.Packages))
 	return nil
 }
 
@@ -60,3 +99,73 @@ func autoConvert_memoryonechost_OperatingSystemConfiguration_To_v1alpha1_Operati
 func Convert_memoryonechost_OperatingSystemConfiguration_To_v1alpha1_OperatingSystemConfiguration(in *memoryonechost.OperatingSystemConfiguration, out *OperatingSystemConfiguration, s conversion.Scope) error {
 	return autoConvert_memoryonechost_OperatingSystemConfiguration_To_v1alpha1_OperatingSystemConfiguration(in, out, s)
 }
+
+func autoConvert_v1alpha1_Packages_To_memoryonechost_Packages(in *Packages, out *memoryonechost.Packages, s conversion.Scope) error {
+	out.Add = *(*[]string)(unsafe.Pointer(&in.Add))
+	out.Remove = *(*[]string)(unsafe.Pointer(&in.Remove))
+	out.InstallTimeout = (*v1.Duration)(unsafe.Pointer(in.InstallTimeout))
+	return nil
+}
+
+// Convert_v1alpha1_Packages_To_memoryonechost_Packages is an autogenerated conversion function.
+func Convert_v1alpha1_Packages_To_memoryonechost_Packages(in *Packages, out *memoryonechost.Packages, s conversion.Scope) error {
+	return autoConvert_v1alpha1_Packages_To_memoryonechost_Packages(in, out, s)
+}
+
+func autoConvert_memoryonechost_Packages_To_v1alpha1_Packages(in *memoryonechost.Packages, out *Packages, s conversion.Scope) error {
+	out.Add = *(*[]string)(unsafe.Pointer(&in.Add))
+	out.Remove = *(*[]string)(unsafe.Pointer(&in.Remove))
+	out.InstallTimeout = (*v1.Duration)(unsafe.Pointer(in.InstallTimeout))
+	return nil
+}
+
+// Convert_memoryonechost_Packages_To_v1alpha1_Packages is an autogenerated conversion function.
+func Convert_memoryonechost_Packages_To_v1alpha1_Packages(in *memoryonechost.Packages, out *Packages, s conversion.Scope) error {
+	return autoConvert_memoryonechost_Packages_To_v1alpha1_Packages(in, out, s)
+}
+
+func autoConvert_v1alpha1_UserDataPart_To_memoryonechost_UserDataPart(in *UserDataPart, out *memoryonechost.UserDataPart, s conversion.Scope) error {
+	out.ContentType = in.ContentType
+	out.Content = (*string)(unsafe.Pointer(in.Content))
+	out.SecretRef = (*memoryonechost.UserDataPartSecretRef)(unsafe.Pointer(in.SecretRef))
+	return nil
+}
+
+// Convert_v1alpha1_UserDataPart_To_memoryonechost_UserDataPart is an autogenerated conversion function.
+func Convert_v1alpha1_UserDataPart_To_memoryonechost_UserDataPart(in *UserDataPart, out *memoryonechost.UserDataPart, s conversion.Scope) error {
+	return autoConvert_v1alpha1_UserDataPart_To_memoryonechost_UserDataPart(in, out, s)
+}
+
+func autoConvert_memoryonechost_UserDataPart_To_v1alpha1_UserDataPart(in *memoryonechost.UserDataPart, out *UserDataPart, s conversion.Scope) error {
+	out.ContentType = in.ContentType
+	out.Content = (*string)(unsafe.Pointer(in.Content))
+	out.SecretRef = (*UserDataPartSecretRef)(unsafe.Pointer(in.SecretRef))
+	return nil
+}
+
+// Convert_memoryonechost_UserDataPart_To_v1alpha1_UserDataPart is an autogenerated conversion function.
+func Convert_memoryonechost_UserDataPart_To_v1alpha1_UserDataPart(in *memoryonechost.UserDataPart, out *UserDataPart, s conversion.Scope) error {
+	return autoConvert_memoryonechost_UserDataPart_To_v1alpha1_UserDataPart(in, out, s)
+}
+
+func autoConvert_v1alpha1_UserDataPartSecretRef_To_memoryonechost_UserDataPartSecretRef(in *UserDataPartSecretRef, out *memoryonechost.UserDataPartSecretRef, s conversion.Scope) error {
+	out.ResourceName = in.ResourceName
+	out.DataKey = in.DataKey
+	return nil
+}
+
+// Convert_v1alpha1_UserDataPartSecretRef_To_memoryonechost_UserDataPartSecretRef is an autogenerated conversion function.
+func Convert_v1alpha1_UserDataPartSecretRef_To_memoryonechost_UserDataPartSecretRef(in *UserDataPartSecretRef, out *memoryonechost.UserDataPartSecretRef, s conversion.Scope) error {
+	return autoConvert_v1alpha1_UserDataPartSecretRef_To_memoryonechost_UserDataPartSecretRef(in, out, s)
+}
+
+func autoConvert_memoryonechost_UserDataPartSecretRef_To_v1alpha1_UserDataPartSecretRef(in *memoryonechost.UserDataPartSecretRef, out *UserDataPartSecretRef, s conversion.Scope) error {
+	out.ResourceName = in.ResourceName
+	out.DataKey = in.DataKey
+	return nil
+}
+
+// Convert_memoryonechost_UserDataPartSecretRef_To_v1alpha1_UserDataPartSecretRef is an autogenerated conversion function.
+func Convert_memoryonechost_UserDataPartSecretRef_To_v1alpha1_UserDataPartSecretRef(in *memoryonechost.UserDataPartSecretRef, out *UserDataPartSecretRef, s conversion.Scope) error {
+	return autoConvert_memoryonechost_UserDataPartSecretRef_To_v1alpha1_UserDataPartSecretRef(in, out, s)
+}
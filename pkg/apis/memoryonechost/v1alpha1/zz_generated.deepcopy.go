@@ -10,6 +10,7 @@
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -34,6 +35,28 @@ func (in *OperatingSystemConfiguration) DeepCopyInto(out *OperatingSystemConfigu
 			(*out)[key] = val
 		}
 	}
+	if in.ExperimentalVsmpParameters != nil {
+		in, out := &in.ExperimentalVsmpParameters, &out.ExperimentalVsmpParameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UserDataFormat != nil {
+		in, out := &in.UserDataFormat, &out.UserDataFormat
+		*out = new(UserDataFormat)
+		**out = **in
+	}
+	if in.AdditionalParts != nil {
+		in, out := &in.AdditionalParts, &out.AdditionalParts
+		*out = make([]UserDataPart, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = new(Packages)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -54,3 +77,76 @@ func (in *OperatingSystemConfiguration) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Packages) DeepCopyInto(out *Packages) {
+	*out = *in
+	if in.Add != nil {
+		in, out := &in.Add, &out.Add
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Remove != nil {
+		in, out := &in.Remove, &out.Remove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstallTimeout != nil {
+		in, out := &in.InstallTimeout, &out.InstallTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Packages.
+func (in *Packages) DeepCopy() *Packages {
+	if in == nil {
+		return nil
+	}
+	out := new(Packages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserDataPart) DeepCopyInto(out *UserDataPart) {
+	*out = *in
+	if in.Content != nil {
+		in, out := &in.Content, &out.Content
+		*out = new(string)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(UserDataPartSecretRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserDataPart.
+func (in *UserDataPart) DeepCopy() *UserDataPart {
+	if in == nil {
+		return nil
+	}
+	out := new(UserDataPart)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserDataPartSecretRef) DeepCopyInto(out *UserDataPartSecretRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserDataPartSecretRef.
+func (in *UserDataPartSecretRef) DeepCopy() *UserDataPartSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(UserDataPartSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"maps"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/helper"
+)
+
+// Convert_v1beta1_OperatingSystemConfiguration_To_memoryonechost_OperatingSystemConfiguration converts the typed
+// fields of the vSMP parameters into `vsmpConfiguration`, where they take precedence.
+func Convert_v1beta1_OperatingSystemConfiguration_To_memoryonechost_OperatingSystemConfiguration(in *OperatingSystemConfiguration, out *memoryonechost.OperatingSystemConfiguration, s conversion.Scope) error {
+	if err := autoConvert_v1beta1_OperatingSystemConfiguration_To_memoryonechost_OperatingSystemConfiguration(in, out, s); err != nil {
+		return err
+	}
+
+	if in.MemTopology == nil && in.SystemMemoryMultiplier == nil {
+		return nil
+	}
+
+	// The map of the internal version shares the memory with the one of the given version, hence it is copied.
+	out.VsmpConfiguration = maps.Clone(in.VsmpConfiguration)
+	if out.VsmpConfiguration == nil {
+		out.VsmpConfiguration = make(map[string]string, 2)
+	}
+	if in.MemTopology != nil {
+		out.VsmpConfiguration[helper.VsmpKeyMemoryTopology] = strconv.Itoa(int(*in.MemTopology))
+	}
+	if in.SystemMemoryMultiplier != nil {
+		out.VsmpConfiguration[helper.VsmpKeySystemMemory] = strconv.Itoa(int(*in.SystemMemoryMultiplier)) + "x"
+	}
+
+	return nil
+}
+
+// Convert_memoryonechost_OperatingSystemConfiguration_To_v1beta1_OperatingSystemConfiguration folds the deprecated
+// `memoryTopology` and `systemMemory` fields of the internal version into `vsmpConfiguration`, see
+// helper.VsmpConfiguration, and converts the parameters with typed fields into them. Parameters whose value cannot be
+// represented by the typed fields without changing the rendered configuration stay in `vsmpConfiguration`.
+func Convert_memoryonechost_OperatingSystemConfiguration_To_v1beta1_OperatingSystemConfiguration(in *memoryonechost.OperatingSystemConfiguration, out *OperatingSystemConfiguration, s conversion.Scope) error {
+	if err := autoConvert_memoryonechost_OperatingSystemConfiguration_To_v1beta1_OperatingSystemConfiguration(in, out, s); err != nil {
+		return err
+	}
+
+	vsmpConfiguration, _ := helper.VsmpConfiguration(in)
+
+	if value, ok := vsmpConfiguration[helper.VsmpKeyMemoryTopology]; ok {
+		if memoryTopology, ok := parseInt32(value); ok {
+			out.MemTopology = ptr.To(memoryTopology)
+			delete(vsmpConfiguration, helper.VsmpKeyMemoryTopology)
+		}
+	}
+	if value, ok := vsmpConfiguration[helper.VsmpKeySystemMemory]; ok {
+		if multiplier, found := strings.CutSuffix(value, "x"); found {
+			if systemMemoryMultiplier, ok := parseInt32(multiplier); ok {
+				out.SystemMemoryMultiplier = ptr.To(systemMemoryMultiplier)
+				delete(vsmpConfiguration, helper.VsmpKeySystemMemory)
+			}
+		}
+	}
+
+	out.VsmpConfiguration = nil
+	if len(vsmpConfiguration) > 0 {
+		out.VsmpConfiguration = vsmpConfiguration
+	}
+
+	return nil
+}
+
+// parseInt32 parses the given decimal integer. It only succeeds if formatting the result yields the given value again,
+// e.g. not for `04`, so that converting the typed field back does not change the rendered configuration.
+func parseInt32(value string) (int32, bool) {
+	i, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || strconv.FormatInt(i, 10) != value {
+		return 0, false
+	}
+	return int32(i), true
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/install"
+	. "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/v1beta1"
+)
+
+var _ = Describe("Conversions", func() {
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		install.Install(scheme)
+	})
+
+	Describe("#Convert_v1beta1_OperatingSystemConfiguration_To_memoryonechost_OperatingSystemConfiguration", func() {
+		It("should convert the typed fields into the vSMP configuration", func() {
+			in := &OperatingSystemConfiguration{
+				MemTopology:            ptr.To[int32](4),
+				SystemMemoryMultiplier: ptr.To[int32](8),
+				VsmpConfiguration:      map[string]string{"mem_topology": "3", "system_memory": "7x", "foo": "bar"},
+			}
+			out := &memoryonechost.OperatingSystemConfiguration{}
+
+			Expect(scheme.Convert(in, out, nil)).To(Succeed())
+			Expect(out.MemoryTopology).To(BeNil())
+			Expect(out.SystemMemory).To(BeNil())
+			Expect(out.VsmpConfiguration).To(Equal(map[string]string{"mem_topology": "4", "system_memory": "8x", "foo": "bar"}))
+			Expect(in.VsmpConfiguration).To(Equal(map[string]string{"mem_topology": "3", "system_memory": "7x", "foo": "bar"}))
+		})
+
+		It("should convert the packages", func() {
+			in := &OperatingSystemConfiguration{Packages: &Packages{Add: []string{"open-iscsi"}, Remove: []string{"wget"}, InstallTimeout: &metav1.Duration{Duration: time.Minute}}}
+			out := &memoryonechost.OperatingSystemConfiguration{}
+
+			Expect(scheme.Convert(in, out, nil)).To(Succeed())
+			Expect(out.Packages).To(Equal(&memoryonechost.Packages{Add: []string{"open-iscsi"}, Remove: []string{"wget"}, InstallTimeout: &metav1.Duration{Duration: time.Minute}}))
+		})
+
+		It("should keep the vSMP configuration without typed fields", func() {
+			in := &OperatingSystemConfiguration{VsmpConfiguration: map[string]string{"foo": "bar"}}
+			out := &memoryonechost.OperatingSystemConfiguration{}
+
+			Expect(scheme.Convert(in, out, nil)).To(Succeed())
+			Expect(out.VsmpConfiguration).To(Equal(map[string]string{"foo": "bar"}))
+		})
+	})
+
+	Describe("#Convert_memoryonechost_OperatingSystemConfiguration_To_v1beta1_OperatingSystemConfiguration", func() {
+		It("should fold the deprecated fields into the typed fields", func() {
+			in := &memoryonechost.OperatingSystemConfiguration{
+				MemoryTopology:    ptr.To("4;foo=bar"),
+				SystemMemory:      ptr.To("8x"),
+				VsmpConfiguration: map[string]string{"system_memory": "6x", "abc": "def"},
+			}
+			out := &OperatingSystemConfiguration{}
+
+			Expect(scheme.Convert(in, out, nil)).To(Succeed())
+			Expect(out).To(Equal(&OperatingSystemConfiguration{
+				MemTopology:            ptr.To[int32](4),
+				SystemMemoryMultiplier: ptr.To[int32](8),
+				VsmpConfiguration:      map[string]string{"foo": "bar", "abc": "def"},
+			}))
+		})
+
+		It("should keep parameters which cannot be represented by the typed fields in the vSMP configuration", func() {
+			in := &memoryonechost.OperatingSystemConfiguration{VsmpConfiguration: map[string]string{"mem_topology": "04", "system_memory": "6"}}
+			out := &OperatingSystemConfiguration{}
+
+			Expect(scheme.Convert(in, out, nil)).To(Succeed())
+			Expect(out.MemTopology).To(BeNil())
+			Expect(out.SystemMemoryMultiplier).To(BeNil())
+			Expect(out.VsmpConfiguration).To(Equal(map[string]string{"mem_topology": "04", "system_memory": "6"}))
+		})
+
+		DescribeTable("should keep system memory which is not a multiplier in the vSMP configuration",
+			func(systemMemory string) {
+				in := &memoryonechost.OperatingSystemConfiguration{VsmpConfiguration: map[string]string{"system_memory": systemMemory}}
+				out := &OperatingSystemConfiguration{}
+
+				Expect(scheme.Convert(in, out, nil)).To(Succeed())
+				Expect(out.SystemMemoryMultiplier).To(BeNil())
+				Expect(out.VsmpConfiguration).To(Equal(map[string]string{"system_memory": systemMemory}))
+			},
+			Entry("upper-case X", "6X"),
+			Entry("leading zero", "06x"),
+			Entry("out of range", "9999999999x"),
+			Entry("only the suffix", "x"),
+		)
+
+		It("should render the same configuration after a round trip", func() {
+			in := &memoryonechost.OperatingSystemConfiguration{
+				MemoryTopology:    ptr.To("2"),
+				VsmpConfiguration: map[string]string{"system_memory": "7x", "foo": "bar"},
+			}
+			external := &OperatingSystemConfiguration{}
+			out := &memoryonechost.OperatingSystemConfiguration{}
+
+			Expect(scheme.Convert(in, external, nil)).To(Succeed())
+			Expect(scheme.Convert(external, out, nil)).To(Succeed())
+			Expect(out.VsmpConfiguration).To(Equal(map[string]string{"mem_topology": "2", "system_memory": "7x", "foo": "bar"}))
+		})
+	})
+})
@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +k8s:deepcopy-gen=package
+// +k8s:conversion-gen=github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost
+// +k8s:openapi-gen=true
+// +k8s:defaulter-gen=TypeMeta
+
+//go:generate crd-ref-docs --source-path=. --config=../../../../hack/api-reference/memoryonechost-config.yaml --renderer=markdown --templates-dir=$GARDENER_HACK_DIR/api-reference/template --log-level=ERROR --output-path=../../../../hack/api-reference/memoryonechost-v1beta1.md
+
+// Package v1beta1 contains the v1beta1 version of the API.
+// +groupName=memoryone-chost.os.extensions.gardener.cloud
+package v1beta1 // import "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/v1beta1"
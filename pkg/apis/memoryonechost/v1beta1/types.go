@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OperatingSystemConfiguration allows to specify configuration for the operating system.
+type OperatingSystemConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// MemTopology is the `mem_topology` parameter of vSMP. Defaults to `2`.
+	// +optional
+	MemTopology *int32 `json:"memTopology,omitempty"`
+	// SystemMemoryMultiplier is the `system_memory` parameter of vSMP, i.e. the memory presented to the operating system
+	// as multiple of the physical memory, e.g. `6` for `6x`. Defaults to `6`.
+	// +optional
+	SystemMemoryMultiplier *int32 `json:"systemMemoryMultiplier,omitempty"`
+	// VsmpConfiguration allows to configure any parameter of vSMP. The parameters configured by `memTopology` and
+	// `systemMemoryMultiplier` take precedence.
+	// +optional
+	VsmpConfiguration map[string]string `json:"vsmpConfiguration,omitempty"`
+	// ExperimentalVsmpParameters are keys of `vsmpConfiguration` which are passed to vSMP without being checked against
+	// the catalog of known parameters, e.g. parameters of a newer vSMP version.
+	// +optional
+	ExperimentalVsmpParameters []string `json:"experimentalVsmpParameters,omitempty"`
+	// UserDataFormat is the format of the part of the user data provisioning the nodes, either `script` or
+	// `cloud-config`. The vSMP configuration is always a separate part. Defaults to `script`.
+	// +optional
+	UserDataFormat *UserDataFormat `json:"userDataFormat,omitempty"`
+	// AdditionalParts are parts which are added to the user data between the vSMP configuration and the part
+	// provisioning the nodes, in the given order. Scripts run before the provisioning of the nodes.
+	// +optional
+	AdditionalParts []UserDataPart `json:"additionalParts,omitempty"`
+	// Packages allows to configure the packages installed on the nodes during provisioning on top of the default
+	// packages (`wget`, `socat`, `jq` and `nfs-client`), like the `packages` of the suse-chost provider configuration.
+	// +optional
+	Packages *Packages `json:"packages,omitempty"`
+}
+
+// Packages allows to add and remove packages installed on the nodes during provisioning.
+type Packages struct {
+	// Add is a list of packages which are installed in addition to the default packages. Services required by known
+	// packages (e.g., `iscsid` for `open-iscsi`) are enabled automatically.
+	// +optional
+	Add []string `json:"add,omitempty"`
+	// Remove is a list of default packages which are not installed. If all default packages are removed and no
+	// package is added, no package is installed at all.
+	// +optional
+	Remove []string `json:"remove,omitempty"`
+	// InstallTimeout is the time after which the provisioning gives up installing the packages, if the installation
+	// keeps failing, e.g., because the package manager is locked or the repositories are not reachable. Defaults to
+	// 10m.
+	// +optional
+	InstallTimeout *metav1.Duration `json:"installTimeout,omitempty"`
+}
+
+// UserDataFormat is the format of the user data of the nodes.
+type UserDataFormat string
+
+const (
+	// UserDataFormatScript renders the user data as a bash script.
+	UserDataFormatScript UserDataFormat = "script"
+	// UserDataFormatCloudConfig renders the user data as cloud-config, files and units are written with `write_files`
+	// and the remaining steps of the provisioning run as `runcmd` entries.
+	UserDataFormatCloudConfig UserDataFormat = "cloud-config"
+)
+
+// UserDataPart is an additional part of the user data.
+type UserDataPart struct {
+	// ContentType is the content type of the part, either `text/x-shellscript` or `text/cloud-config`.
+	ContentType string `json:"contentType"`
+	// Content is the content of the part. Either content or secretRef must be set.
+	// +optional
+	Content *string `json:"content,omitempty"`
+	// SecretRef references a Secret holding the content of the part. Either content or secretRef must be set.
+	// +optional
+	SecretRef *UserDataPartSecretRef `json:"secretRef,omitempty"`
+}
+
+// UserDataPartSecretRef references the content of an additional part of the user data in a Secret.
+type UserDataPartSecretRef struct {
+	// ResourceName is the name of a resource in the Shoot's `spec.resources` which references the Secret.
+	ResourceName string `json:"resourceName"`
+	// DataKey is the key in the data of the Secret holding the content.
+	DataKey string `json:"dataKey"`
+}
@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/helper"
+	susechostvalidation "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost/validation"
+)
+
+// vsmpKeyRegex matches the keys vSMP MemoryOne understands, e.g. `mem_topology` or `pci_dev_filter`.
+var vsmpKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+var (
+	// supportedUserDataFormats are the formats the part of the user data provisioning the nodes can be rendered in.
+	supportedUserDataFormats = sets.New(string(memoryonechost.UserDataFormatScript), string(memoryonechost.UserDataFormatCloudConfig))
+	// supportedUserDataPartContentTypes are the content types of additional parts of the user data cloud-init handles.
+	supportedUserDataPartContentTypes = sets.New("text/x-shellscript", "text/cloud-config")
+)
+
+// ValidateOperatingSystemConfiguration validates a memoryone-chost OperatingSystemConfiguration.
+func ValidateOperatingSystemConfiguration(config *memoryonechost.OperatingSystemConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if config == nil {
+		return allErrs
+	}
+
+	allErrs = append(allErrs, validateLegacyValue(config.MemoryTopology, fldPath.Child("memoryTopology"))...)
+	allErrs = append(allErrs, validateLegacyValue(config.SystemMemory, fldPath.Child("systemMemory"))...)
+	allErrs = append(allErrs, validateVsmpConfiguration(config.VsmpConfiguration, sets.New(config.ExperimentalVsmpParameters...), fldPath.Child("vsmpConfiguration"))...)
+	allErrs = append(allErrs, validateExperimentalVsmpParameters(config.ExperimentalVsmpParameters, fldPath.Child("experimentalVsmpParameters"))...)
+
+	if config.UserDataFormat != nil && !supportedUserDataFormats.Has(string(*config.UserDataFormat)) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("userDataFormat"), *config.UserDataFormat, sets.List(supportedUserDataFormats)))
+	}
+
+	allErrs = append(allErrs, validateAdditionalParts(config.AdditionalParts, ptr.Deref(config.UserDataFormat, memoryonechost.UserDataFormatScript), fldPath.Child("additionalParts"))...)
+
+	if config.Packages != nil {
+		allErrs = append(allErrs, susechostvalidation.ValidatePackages(config.Packages.Add, config.Packages.Remove, config.Packages.InstallTimeout, fldPath.Child("packages"))...)
+	}
+
+	return allErrs
+}
+
+func validateAdditionalParts(parts []memoryonechost.UserDataPart, userDataFormat memoryonechost.UserDataFormat, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, part := range parts {
+		idxPath := fldPath.Index(i)
+
+		if !supportedUserDataPartContentTypes.Has(part.ContentType) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("contentType"), part.ContentType, sets.List(supportedUserDataPartContentTypes)))
+		} else if part.ContentType == "text/cloud-config" && userDataFormat == memoryonechost.UserDataFormatCloudConfig {
+			// cloud-init merges cloud-config parts, the lists of the part provisioning the nodes would be replaced.
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("contentType"), "cloud-config parts are not supported if the user data format is cloud-config"))
+		}
+
+		switch {
+		case part.Content == nil && part.SecretRef == nil:
+			allErrs = append(allErrs, field.Required(idxPath, "either content or secretRef must be set"))
+		case part.Content != nil && part.SecretRef != nil:
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("secretRef"), "must not be set together with content"))
+		case part.SecretRef != nil:
+			if len(part.SecretRef.ResourceName) == 0 {
+				allErrs = append(allErrs, field.Required(idxPath.Child("secretRef", "resourceName"), "must not be empty"))
+			}
+			if len(part.SecretRef.DataKey) == 0 {
+				allErrs = append(allErrs, field.Required(idxPath.Child("secretRef", "dataKey"), "must not be empty"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateVsmpConfiguration(vsmpConfiguration map[string]string, experimentalParameters sets.Set[string], fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// iterate in a stable order so that the reported errors are stable as well
+	for _, key := range slices.Sorted(maps.Keys(vsmpConfiguration)) {
+		value := vsmpConfiguration[key]
+		keyPath := fldPath.Key(key)
+
+		validKey := true
+		if len(key) == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath, key, "keys must not be empty"))
+			validKey = false
+		} else if !vsmpKeyRegex.MatchString(key) {
+			allErrs = append(allErrs, field.Invalid(keyPath, key, "key must consist of alphanumeric characters, '_', '-' or '.'"))
+			validKey = false
+		}
+
+		validValue := true
+		if strings.Contains(value, ";") {
+			allErrs = append(allErrs, field.Invalid(keyPath, value, "value must not contain ';'"))
+			validValue = false
+		}
+		if containsControlCharacter(value) {
+			allErrs = append(allErrs, field.Invalid(keyPath, value, "value must not contain line breaks or other control characters"))
+			validValue = false
+		}
+
+		// Experimental parameters are passed as they are, e.g. if they are not yet in the catalog. Unknown parameters and
+		// values out of range are not rejected either, as they used to be passed to vSMP before the catalog existed. The
+		// admission webhook returns warnings for them instead, see helper.VsmpCatalogWarnings.
+		if !validKey || !validValue || experimentalParameters.Has(key) {
+			continue
+		}
+
+		definition, ok := helper.LookupVsmpParameter(key)
+		if !ok {
+			continue
+		}
+		if err := definition.ValidateValue(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(keyPath, value, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+func validateExperimentalVsmpParameters(keys []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	seen := sets.New[string]()
+	for i, key := range keys {
+		idxPath := fldPath.Index(i)
+
+		if !vsmpKeyRegex.MatchString(key) {
+			allErrs = append(allErrs, field.Invalid(idxPath, key, "key must consist of alphanumeric characters, '_', '-' or '.'"))
+		}
+		if seen.Has(key) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, key))
+		}
+		seen.Insert(key)
+	}
+
+	return allErrs
+}
+
+// validateLegacyValue validates the deprecated `memoryTopology` and `systemMemory` fields. For backwards compatibility,
+// they may still contain additional key-value pairs separated by ';', but they must stay on a single line.
+func validateLegacyValue(value *string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if value == nil {
+		return allErrs
+	}
+
+	if len(strings.TrimSpace(*value)) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, *value, "value must not be empty"))
+	}
+	if containsControlCharacter(*value) {
+		allErrs = append(allErrs, field.Invalid(fldPath, *value, "value must not contain line breaks or other control characters"))
+	}
+
+	return allErrs
+}
+
+func containsControlCharacter(s string) bool {
+	return strings.ContainsFunc(s, func(r rune) bool {
+		return r < 0x20 || r == 0x7f
+	})
+}
@@ -0,0 +1,244 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost"
+	. "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/validation"
+)
+
+var _ = Describe("Validation", func() {
+	var (
+		fldPath *field.Path
+		config  *memoryonechost.OperatingSystemConfiguration
+	)
+
+	BeforeEach(func() {
+		fldPath = field.NewPath("providerConfig")
+		config = &memoryonechost.OperatingSystemConfiguration{
+			MemoryTopology: ptr.To("3"),
+			SystemMemory:   ptr.To("7x"),
+			VsmpConfiguration: map[string]string{
+				"debug_features": "&0xffffff",
+				"pci_dev_filter": `"00:0a:ce"`,
+			},
+		}
+	})
+
+	Describe("#ValidateOperatingSystemConfiguration", func() {
+		It("should allow a nil configuration", func() {
+			Expect(ValidateOperatingSystemConfiguration(nil, fldPath)).To(BeEmpty())
+		})
+
+		It("should allow a valid configuration", func() {
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(BeEmpty())
+		})
+
+		It("should allow injecting key-value pairs through the legacy fields", func() {
+			config.MemoryTopology = ptr.To("3;debug_features=&0xffffffff")
+
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid empty or multi-line legacy values", func() {
+			config.MemoryTopology = ptr.To(" ")
+			config.SystemMemory = ptr.To("7x\nmem_topology=2")
+
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("providerConfig.memoryTopology"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("providerConfig.systemMemory"),
+				})),
+			))
+		})
+
+		It("should validate the packages", func() {
+			config.Packages = &memoryonechost.Packages{
+				Add:            []string{"open-iscsi", "-foo"},
+				Remove:         []string{"open-iscsi"},
+				InstallTimeout: &metav1.Duration{Duration: time.Millisecond},
+			}
+
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("providerConfig.packages.add[1]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("providerConfig.packages.installTimeout")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("providerConfig.packages.add[0]")})),
+			))
+		})
+
+		It("should allow the supported user data formats", func() {
+			config.UserDataFormat = ptr.To(memoryonechost.UserDataFormatCloudConfig)
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(BeEmpty())
+
+			config.UserDataFormat = ptr.To(memoryonechost.UserDataFormatScript)
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid unsupported user data formats", func() {
+			config.UserDataFormat = ptr.To(memoryonechost.UserDataFormat("ignition"))
+
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("providerConfig.userDataFormat"),
+				})),
+			))
+		})
+
+		Context("additional parts", func() {
+			BeforeEach(func() {
+				config.AdditionalParts = []memoryonechost.UserDataPart{
+					{ContentType: "text/x-shellscript", Content: ptr.To("#!/bin/bash\necho foo\n")},
+					{ContentType: "text/cloud-config", SecretRef: &memoryonechost.UserDataPartSecretRef{ResourceName: "cloud-config", DataKey: "cloud-config.yaml"}},
+				}
+			})
+
+			It("should allow valid parts", func() {
+				Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(BeEmpty())
+			})
+
+			DescribeTable("should forbid invalid parts",
+				func(mutate func(*memoryonechost.UserDataPart), errorType field.ErrorType, fieldName string) {
+					mutate(&config.AdditionalParts[1])
+
+					Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+						PointTo(MatchFields(IgnoreExtras, Fields{
+							"Type":  Equal(errorType),
+							"Field": Equal(fieldName),
+						})),
+					))
+				},
+				Entry("unsupported content type", func(p *memoryonechost.UserDataPart) { p.ContentType = "text/x-vsmp" }, field.ErrorTypeNotSupported, "providerConfig.additionalParts[1].contentType"),
+				Entry("neither content nor secret", func(p *memoryonechost.UserDataPart) { p.SecretRef = nil }, field.ErrorTypeRequired, "providerConfig.additionalParts[1]"),
+				Entry("content and secret", func(p *memoryonechost.UserDataPart) { p.Content = ptr.To("foo") }, field.ErrorTypeForbidden, "providerConfig.additionalParts[1].secretRef"),
+				Entry("empty resource name", func(p *memoryonechost.UserDataPart) { p.SecretRef.ResourceName = "" }, field.ErrorTypeRequired, "providerConfig.additionalParts[1].secretRef.resourceName"),
+				Entry("empty data key", func(p *memoryonechost.UserDataPart) { p.SecretRef.DataKey = "" }, field.ErrorTypeRequired, "providerConfig.additionalParts[1].secretRef.dataKey"),
+			)
+
+			It("should forbid cloud-config parts if the user data format is cloud-config", func() {
+				config.UserDataFormat = ptr.To(memoryonechost.UserDataFormatCloudConfig)
+
+				Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeForbidden),
+						"Field": Equal("providerConfig.additionalParts[1].contentType"),
+					})),
+				))
+			})
+		})
+
+		It("should forbid empty keys", func() {
+			config.VsmpConfiguration[""] = "foo"
+
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("providerConfig.vsmpConfiguration"),
+					"Detail": Equal("keys must not be empty"),
+				})),
+			))
+		})
+
+		DescribeTable("should forbid invalid keys",
+			func(key string) {
+				config.VsmpConfiguration[key] = "foo"
+
+				Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":     Equal(field.ErrorTypeInvalid),
+						"Field":    Equal("providerConfig.vsmpConfiguration[" + key + "]"),
+						"BadValue": Equal(key),
+					})),
+				))
+			},
+			Entry("key with '='", "foo=bar"),
+			Entry("key with a line break", "foo\nbar"),
+			Entry("key with whitespace", "foo bar"),
+			Entry("key with ';'", "foo;bar"),
+		)
+
+		DescribeTable("should forbid invalid values",
+			func(value, detail string) {
+				config.VsmpConfiguration["foo"] = value
+
+				Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":     Equal(field.ErrorTypeInvalid),
+						"Field":    Equal("providerConfig.vsmpConfiguration[foo]"),
+						"BadValue": Equal(value),
+						"Detail":   Equal(detail),
+					})),
+				))
+			},
+			Entry("value with ';'", "bar; foobar: barfoo", "value must not contain ';'"),
+			Entry("value with a line break", "bar\nsystem_memory=1x", "value must not contain line breaks or other control characters"),
+			Entry("value with a carriage return", "bar\r", "value must not contain line breaks or other control characters"),
+		)
+
+		It("should allow unknown parameters with any value", func() {
+			config.VsmpConfiguration["mem_topolgy"] = "3"
+			config.VsmpConfiguration["foo"] = "bar"
+
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(BeEmpty())
+		})
+
+		DescribeTable("should forbid malformed values of known parameters",
+			func(key, value, detail string) {
+				config.VsmpConfiguration[key] = value
+
+				Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":     Equal(field.ErrorTypeInvalid),
+						"Field":    Equal("providerConfig.vsmpConfiguration[" + key + "]"),
+						"BadValue": Equal(value),
+						"Detail":   Equal(detail),
+					})),
+				))
+			},
+			Entry("integer which is not a number", "mem_topology", "two", "value must be an integer, e.g. 2"),
+			Entry("multiplier without x", "system_memory", "6", "value must be a multiplier, e.g. 6x"),
+			Entry("hex mask without &", "debug_features", "0xff", "value must be a hex mask of at most 64 bits, e.g. &0xff"),
+			Entry("hex mask exceeding 64 bits", "debug_features", "&0x1ffffffffffffffff", "value must be a hex mask of at most 64 bits, e.g. &0xff"),
+			Entry("string without quotes", "pci_dev_filter", "00:0a:ce", `value must be a string in double quotes with escaped quotes inside, e.g. "00:0a:ce"`),
+			Entry("string with unescaped quotes", "pci_dev_filter", `"00"0a"`, `value must be a string in double quotes with escaped quotes inside, e.g. "00:0a:ce"`),
+		)
+
+		It("should allow experimental parameters with any value", func() {
+			config.VsmpConfiguration["foo"] = "bar"
+			config.VsmpConfiguration["mem_topology"] = "auto"
+			config.ExperimentalVsmpParameters = []string{"foo", "mem_topology"}
+
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid invalid or duplicate experimental parameters", func() {
+			config.ExperimentalVsmpParameters = []string{"foo", "foo bar", "foo"}
+
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("providerConfig.experimentalVsmpParameters[1]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("providerConfig.experimentalVsmpParameters[2]"),
+				})),
+			))
+		})
+	})
+})
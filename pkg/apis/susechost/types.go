@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package susechost
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OperatingSystemConfiguration allows to specify configuration for the suse-chost operating system.
+type OperatingSystemConfiguration struct {
+	metav1.TypeMeta
+
+	// Packages allows to configure the packages installed on the nodes during provisioning on top of the default
+	// packages.
+	Packages *Packages
+	// Repositories are zypper repositories which are configured on the nodes before packages are installed.
+	Repositories []Repository
+	// UserDataFormat is the format of the user data of the nodes.
+	UserDataFormat *UserDataFormat
+	// UnifiedCgroupHierarchy makes the nodes boot with the unified cgroup hierarchy (cgroup v2).
+	UnifiedCgroupHierarchy *bool
+}
+
+// Repository is a zypper repository, e.g. of an internal SUSE RMT or SMT mirror.
+type Repository struct {
+	// Name is the alias of the repository.
+	Name string
+	// URL is the base URL of the repository.
+	URL string
+	// GPGKey is the ASCII armored public GPG key the repository is signed with.
+	GPGKey *string
+	// Priority is the priority of the repository.
+	Priority *int32
+	// CredentialsResourceName is the name of a resource in the Shoot's `spec.resources` which references a Secret
+	// with the `username` and `password` for the repository. Repositories with credentials are not configured by the
+	// provision script but by gardener-node-agent, so that the credentials are not part of the user data.
+	CredentialsResourceName *string
+}
+
+// Packages allows to add and remove packages installed on the nodes during provisioning.
+type Packages struct {
+	// Add is a list of packages which are installed in addition to the default packages.
+	Add []string
+	// Remove is a list of default packages which are not installed.
+	Remove []string
+	// InstallTimeout is the time after which the provisioning gives up installing the packages.
+	InstallTimeout *metav1.Duration
+}
+
+// UserDataFormat is the format of the user data of the nodes.
+type UserDataFormat string
+
+const (
+	// UserDataFormatScript renders the user data as a bash script.
+	UserDataFormatScript UserDataFormat = "script"
+	// UserDataFormatCloudConfig renders the user data as cloud-config.
+	UserDataFormatCloudConfig UserDataFormat = "cloud-config"
+)
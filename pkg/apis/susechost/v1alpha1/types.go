@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OperatingSystemConfiguration allows to specify configuration for the suse-chost operating system.
+type OperatingSystemConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Packages allows to configure the packages installed on the nodes during provisioning on top of the default
+	// packages (`wget`, `socat`, `jq` and `nfs-client`).
+	// +optional
+	Packages *Packages `json:"packages,omitempty"`
+	// Repositories are zypper repositories which are configured on the nodes before packages are installed, in
+	// addition to the repositories configured by the operator of the extension.
+	// +optional
+	Repositories []Repository `json:"repositories,omitempty"`
+	// UserDataFormat is the format of the user data of the nodes, either `script` or `cloud-config`. Defaults to
+	// `script`.
+	// +optional
+	UserDataFormat *UserDataFormat `json:"userDataFormat,omitempty"`
+	// UnifiedCgroupHierarchy makes the nodes boot with the unified cgroup hierarchy (cgroup v2) instead of cgroup v1 if
+	// set to `true`. If unset, the deprecated `suse-chost.os.extensions.gardener.cloud/unified-cgroup-hierarchy`
+	// annotation of the Shoot is considered.
+	// +optional
+	UnifiedCgroupHierarchy *bool `json:"unifiedCgroupHierarchy,omitempty"`
+}
+
+// Repository is a zypper repository, e.g. of an internal SUSE RMT or SMT mirror.
+type Repository struct {
+	// Name is the alias of the repository.
+	Name string `json:"name"`
+	// URL is the base URL of the repository.
+	URL string `json:"url"`
+	// GPGKey is the ASCII armored public GPG key the repository is signed with. It is imported before packages are
+	// installed.
+	// +optional
+	GPGKey *string `json:"gpgKey,omitempty"`
+	// Priority is the priority of the repository, lower values mean higher priority. Defaults to zypper's default
+	// priority.
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+	// CredentialsResourceName is the name of a resource in the Shoot's `spec.resources` which references a Secret
+	// with the `username` and `password` for the repository. Repositories with credentials are not configured by the
+	// provision script but by gardener-node-agent, so that the credentials are not part of the user data.
+	// +optional
+	CredentialsResourceName *string `json:"credentialsResourceName,omitempty"`
+}
+
+// Packages allows to add and remove packages installed on the nodes during provisioning.
+type Packages struct {
+	// Add is a list of packages which are installed in addition to the default packages. Services required by known
+	// packages (e.g., `iscsid` for `open-iscsi`) are enabled automatically.
+	// +optional
+	Add []string `json:"add,omitempty"`
+	// Remove is a list of default packages which are not installed. If all default packages are removed and no
+	// package is added, no package is installed at all.
+	// +optional
+	Remove []string `json:"remove,omitempty"`
+	// InstallTimeout is the time after which the provisioning gives up installing the packages, if the installation
+	// keeps failing, e.g., because the package manager is locked or the repositories are not reachable. Defaults to
+	// 10m.
+	// +optional
+	InstallTimeout *metav1.Duration `json:"installTimeout,omitempty"`
+}
+
+// UserDataFormat is the format of the user data of the nodes.
+type UserDataFormat string
+
+const (
+	// UserDataFormatScript renders the user data as a bash script.
+	UserDataFormatScript UserDataFormat = "script"
+	// UserDataFormatCloudConfig renders the user data as cloud-config, files and units are written with `write_files`
+	// and the remaining steps of the provisioning run as `runcmd` entries.
+	UserDataFormatCloudConfig UserDataFormat = "cloud-config"
+)
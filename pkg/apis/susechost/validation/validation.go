@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost"
+)
+
+var (
+	// repositoryNameRegex matches valid repository aliases. The alias is used as file name on the nodes.
+	repositoryNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+	// supportedRepositoryURLSchemes are the URL schemes of repositories which are reachable during provisioning.
+	supportedRepositoryURLSchemes = sets.New("http", "https", "ftp", "nfs", "file", "dir")
+	// supportedUserDataFormats are the formats the user data can be rendered in.
+	supportedUserDataFormats = sets.New(string(susechost.UserDataFormatScript), string(susechost.UserDataFormatCloudConfig))
+)
+
+// packageNameRegex matches valid RPM package names, e.g. `open-iscsi` or `libstdc++6`.
+var packageNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.+-]*$`)
+
+// ValidateOperatingSystemConfiguration validates a suse-chost OperatingSystemConfiguration.
+func ValidateOperatingSystemConfiguration(config *susechost.OperatingSystemConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if config == nil {
+		return allErrs
+	}
+
+	if config.Packages != nil {
+		allErrs = append(allErrs, ValidatePackages(config.Packages.Add, config.Packages.Remove, config.Packages.InstallTimeout, fldPath.Child("packages"))...)
+	}
+
+	if config.UserDataFormat != nil && !supportedUserDataFormats.Has(string(*config.UserDataFormat)) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("userDataFormat"), *config.UserDataFormat, sets.List(supportedUserDataFormats)))
+	}
+
+	repositoryNames := sets.New[string]()
+	for i, repository := range config.Repositories {
+		idxPath := fldPath.Child("repositories").Index(i)
+
+		allErrs = append(allErrs, ValidateRepository(repository.Name, repository.URL, repository.GPGKey, repository.Priority, idxPath)...)
+		if repositoryNames.Has(repository.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), repository.Name))
+		}
+		repositoryNames.Insert(repository.Name)
+
+		if repository.CredentialsResourceName != nil && len(*repository.CredentialsResourceName) == 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("credentialsResourceName"), *repository.CredentialsResourceName, "must not be empty"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateRepository validates the fields of a zypper repository. It is shared with the validation of the controller
+// configuration, which configures repositories for all nodes.
+func ValidateRepository(name, rawURL string, gpgKey *string, priority *int32, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(name) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "repository name must not be empty"))
+	} else if !repositoryNameRegex.MatchString(name) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), name, "repository name must start with an alphanumeric character and only consist of alphanumeric characters, '_', '.' or '-'"))
+	}
+
+	if len(rawURL) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("url"), "repository URL must not be empty"))
+	} else if u, err := url.Parse(rawURL); err != nil || strings.ContainsFunc(rawURL, unicode.IsSpace) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), rawURL, "repository URL must be a valid URL"))
+	} else if !supportedRepositoryURLSchemes.Has(u.Scheme) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("url"), u.Scheme, sets.List(supportedRepositoryURLSchemes)))
+	}
+
+	if gpgKey != nil && !strings.Contains(*gpgKey, "-----BEGIN PGP PUBLIC KEY BLOCK-----") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("gpgKey"), "", "GPG key must be an ASCII armored public key"))
+	}
+
+	if priority != nil && *priority < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("priority"), *priority, "priority must be positive"))
+	}
+
+	return allErrs
+}
+
+// ValidatePackages validates the packages to add and remove and the install timeout of the `packages` of a provider
+// configuration. It is also used for the memoryone-chost provider configuration.
+func ValidatePackages(add, remove []string, installTimeout *metav1.Duration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validatePackageNames(add, fldPath.Child("add"))...)
+	allErrs = append(allErrs, validatePackageNames(remove, fldPath.Child("remove"))...)
+
+	if installTimeout != nil && installTimeout.Duration < time.Second {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("installTimeout"), installTimeout.Duration.String(), "must be at least 1s"))
+	}
+
+	removed := sets.New(remove...)
+	for i, name := range add {
+		if removed.Has(name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("add").Index(i), name, "package must not be added and removed at the same time"))
+		}
+	}
+
+	return allErrs
+}
+
+func validatePackageNames(names []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := sets.New[string]()
+
+	for i, name := range names {
+		idxPath := fldPath.Index(i)
+
+		if !packageNameRegex.MatchString(name) {
+			allErrs = append(allErrs, field.Invalid(idxPath, name, "package name must start with an alphanumeric character and only consist of alphanumeric characters, '_', '.', '+' or '-'"))
+		}
+		if seen.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, name))
+		}
+		seen.Insert(name)
+	}
+
+	return allErrs
+}
@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost"
+	. "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost/validation"
+)
+
+var _ = Describe("Validation", func() {
+	var (
+		fldPath *field.Path
+		config  *susechost.OperatingSystemConfiguration
+	)
+
+	BeforeEach(func() {
+		fldPath = field.NewPath("providerConfig")
+		config = &susechost.OperatingSystemConfiguration{
+			Packages: &susechost.Packages{
+				Add:            []string{"open-iscsi", "multipath-tools", "libstdc++6"},
+				Remove:         []string{"nfs-client"},
+				InstallTimeout: &metav1.Duration{Duration: 5 * time.Minute},
+			},
+		}
+	})
+
+	Describe("#ValidateOperatingSystemConfiguration", func() {
+		It("should allow a nil configuration", func() {
+			Expect(ValidateOperatingSystemConfiguration(nil, fldPath)).To(BeEmpty())
+		})
+
+		It("should allow an empty configuration", func() {
+			Expect(ValidateOperatingSystemConfiguration(&susechost.OperatingSystemConfiguration{}, fldPath)).To(BeEmpty())
+		})
+
+		It("should allow a valid configuration", func() {
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(BeEmpty())
+		})
+
+		DescribeTable("should forbid invalid package names",
+			func(name string) {
+				config.Packages.Add = append(config.Packages.Add, name)
+				config.Packages.Remove = append(config.Packages.Remove, name)
+
+				Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ContainElements(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":     Equal(field.ErrorTypeInvalid),
+						"Field":    Equal("providerConfig.packages.add[3]"),
+						"BadValue": Equal(name),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":     Equal(field.ErrorTypeInvalid),
+						"Field":    Equal("providerConfig.packages.remove[1]"),
+						"BadValue": Equal(name),
+					})),
+				))
+			},
+			Entry("empty name", ""),
+			Entry("name with whitespace", "foo bar"),
+			Entry("name with shell meta characters", "foo;reboot"),
+			Entry("option", "--no-gpg-checks"),
+		)
+
+		It("should forbid duplicate packages", func() {
+			config.Packages.Add = append(config.Packages.Add, "open-iscsi")
+
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("providerConfig.packages.add[3]"),
+				})),
+			))
+		})
+
+		It("should forbid install timeouts shorter than a second", func() {
+			config.Packages.InstallTimeout = &metav1.Duration{Duration: 500 * time.Millisecond}
+
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("providerConfig.packages.installTimeout"),
+				})),
+			))
+		})
+
+		It("should forbid adding and removing the same package", func() {
+			config.Packages.Add = append(config.Packages.Add, "nfs-client")
+
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("providerConfig.packages.add[3]"),
+					"Detail": Equal("package must not be added and removed at the same time"),
+				})),
+			))
+		})
+
+		It("should allow the supported user data formats", func() {
+			config.UserDataFormat = ptr.To(susechost.UserDataFormatCloudConfig)
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(BeEmpty())
+
+			config.UserDataFormat = ptr.To(susechost.UserDataFormatScript)
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid unsupported user data formats", func() {
+			config.UserDataFormat = ptr.To(susechost.UserDataFormat("ignition"))
+
+			Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("providerConfig.userDataFormat"),
+				})),
+			))
+		})
+
+		Context("repositories", func() {
+			BeforeEach(func() {
+				config.Repositories = []susechost.Repository{{
+					Name:                    "rmt",
+					URL:                     "https://rmt.example.com/repo/SUSE/Products/SLE-Product-SLES/15-SP5/x86_64/product",
+					GPGKey:                  ptr.To("-----BEGIN PGP PUBLIC KEY BLOCK-----\nfoo\n-----END PGP PUBLIC KEY BLOCK-----\n"),
+					Priority:                ptr.To[int32](90),
+					CredentialsResourceName: ptr.To("rmt-credentials"),
+				}}
+			})
+
+			It("should allow a valid repository", func() {
+				Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(BeEmpty())
+			})
+
+			DescribeTable("should forbid invalid repositories",
+				func(mutate func(*susechost.Repository), errorType field.ErrorType, fieldName string) {
+					mutate(&config.Repositories[0])
+
+					Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+						PointTo(MatchFields(IgnoreExtras, Fields{
+							"Type":  Equal(errorType),
+							"Field": Equal(fieldName),
+						})),
+					))
+				},
+				Entry("empty name", func(r *susechost.Repository) { r.Name = "" }, field.ErrorTypeRequired, "providerConfig.repositories[0].name"),
+				Entry("name with path separator", func(r *susechost.Repository) { r.Name = "../rmt" }, field.ErrorTypeInvalid, "providerConfig.repositories[0].name"),
+				Entry("empty URL", func(r *susechost.Repository) { r.URL = "" }, field.ErrorTypeRequired, "providerConfig.repositories[0].url"),
+				Entry("URL with whitespace", func(r *susechost.Repository) { r.URL = "https://rmt.example.com/ repo" }, field.ErrorTypeInvalid, "providerConfig.repositories[0].url"),
+				Entry("unsupported URL scheme", func(r *susechost.Repository) { r.URL = "gopher://rmt.example.com" }, field.ErrorTypeNotSupported, "providerConfig.repositories[0].url"),
+				Entry("GPG key without public key block", func(r *susechost.Repository) { r.GPGKey = ptr.To("foo") }, field.ErrorTypeInvalid, "providerConfig.repositories[0].gpgKey"),
+				Entry("non-positive priority", func(r *susechost.Repository) { r.Priority = ptr.To[int32](0) }, field.ErrorTypeInvalid, "providerConfig.repositories[0].priority"),
+				Entry("empty credentials resource name", func(r *susechost.Repository) { r.CredentialsResourceName = ptr.To("") }, field.ErrorTypeInvalid, "providerConfig.repositories[0].credentialsResourceName"),
+			)
+
+			It("should forbid duplicate repository names", func() {
+				config.Repositories = append(config.Repositories, susechost.Repository{Name: "rmt", URL: "https://rmt.example.com/other"})
+
+				Expect(ValidateOperatingSystemConfiguration(config, fldPath)).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeDuplicate),
+						"Field": Equal("providerConfig.repositories[1].name"),
+					})),
+				))
+			})
+		})
+	})
+})
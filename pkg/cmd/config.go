@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config/loader"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config/validation"
+)
+
+// ConfigOptions are command line options that can be set for config.ControllerConfiguration.
+type ConfigOptions struct {
+	// ConfigFilePath is the path to the controller configuration file. It is optional, if it is not set, the
+	// default configuration is used.
+	ConfigFilePath string
+
+	config *Config
+}
+
+// Config is a completed controller configuration.
+type Config struct {
+	// Config is the controller configuration.
+	Config *config.ControllerConfiguration
+}
+
+func (c *ConfigOptions) buildConfig() (*config.ControllerConfiguration, error) {
+	if len(c.ConfigFilePath) == 0 {
+		return &config.ControllerConfiguration{}, nil
+	}
+
+	cfg, err := loader.LoadFromFile(c.ConfigFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading controller configuration from %q: %w", c.ConfigFilePath, err)
+	}
+
+	if errs := validation.ValidateControllerConfiguration(cfg); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid controller configuration: %w", errs.ToAggregate())
+	}
+
+	return cfg, nil
+}
+
+// Complete implements Completer.Complete.
+func (c *ConfigOptions) Complete() error {
+	cfg, err := c.buildConfig()
+	if err != nil {
+		return err
+	}
+
+	c.config = &Config{Config: cfg}
+	return nil
+}
+
+// Completed returns the completed Config. Only call this if `Complete` was successful.
+func (c *ConfigOptions) Completed() *Config {
+	return c.config
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (c *ConfigOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&c.ConfigFilePath, "config-file", "", "Path to the controller configuration file.")
+}
+
+// Apply sets the values of this Config in the given config.ControllerConfiguration.
+func (c *Config) Apply(cfg *config.ControllerConfiguration) {
+	*cfg = *c.Config
+}
@@ -6,19 +6,28 @@ package operatingsystemconfig
 
 import (
 	"context"
-	_ "embed"
 	"fmt"
+	"slices"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/gardener/gardener/extensions/pkg/controller/operatingsystemconfig"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/gardener/gardener/pkg/extensions"
+	"github.com/gardener/gardener/pkg/utils"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
+	memoryonechostapi "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost"
+	memoryonechosthelper "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/helper"
+	susechostapi "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost"
 	"github.com/gardener/gardener-extension-os-suse-chost/pkg/memoryone"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/susechost"
 )
 
 // kubeletFailCgroupV1MinVersion is the first Kubernetes version that defaults
@@ -28,40 +37,79 @@ var kubeletFailCgroupV1MinVersion = semver.MustParse("1.35.0")
 
 // kubeletFailCgroupV1RemovedVersion is the earliest Kubernetes version in which
 // cgroup v1 support (and therefore the --fail-cgroupv1 flag) may be removed from
-// kubelet. KEP-5573 states: "The removal will be done no earlier than 1.38 to
-// maintain the k8s deprecation policy."
-// See https://github.com/kubernetes/enhancements/tree/master/keps/sig-node/5573-remove-cgroup-v1
-// From this version on, passing the flag may cause kubelet to fail to start with
-// an unknown-flag error, so we must not emit it.
-var kubeletFailCgroupV1RemovedVersion = semver.MustParse("1.38.0")
+// kubelet, see susechost.KubeletCgroupV1RemovedVersion.
+// From this version on, kubelet does not start on cgroup v1 nodes anymore, so the
+// reconciliation of cgroup v1 worker pools fails. Shoots with cgroup v1 worker pools
+// are prevented from upgrading to this version by the Shoot validation webhook.
+var kubeletFailCgroupV1RemovedVersion = susechost.KubeletCgroupV1RemovedVersion
 
 type actuator struct {
 	client client.Client
+	config config.ControllerConfiguration
 }
 
 // NewActuator creates a new Actuator that updates the status of the handled OperatingSystemConfig resources.
-func NewActuator(mgr manager.Manager) operatingsystemconfig.Actuator {
+func NewActuator(c client.Client, config config.ControllerConfiguration) operatingsystemconfig.Actuator {
 	return &actuator{
-		client: mgr.GetClient(),
+		client: c,
+		config: config,
 	}
 }
 
-func (a *actuator) Reconcile(ctx context.Context, _ logr.Logger, osc *extensionsv1alpha1.OperatingSystemConfig) ([]byte, []extensionsv1alpha1.Unit, []extensionsv1alpha1.File, *extensionsv1alpha1.InPlaceUpdatesStatus, error) {
+// ProvisionScriptFragments returns the names of the fragments the provision script of the given OperatingSystemConfig
+// consists of with the given controller configuration, in the order they are run. The provision script is not rendered
+// for this, i.e. the secrets referenced by the OperatingSystemConfig are not read.
+func ProvisionScriptFragments(ctx context.Context, c client.Client, config config.ControllerConfiguration, osc *extensionsv1alpha1.OperatingSystemConfig) ([]string, error) {
+	cluster, err := extensions.GetCluster(ctx, c, osc.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	suseCHostConfig, memoryOneConfig, _, err := provisionConfigurations(osc)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &actuator{client: c, config: config}
+	data, err := a.provisionScriptData(osc, cluster, suseCHostConfig, memoryOneConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var fragments []string
+	for _, fragment := range includedProvisionScriptFragments(data, a.disabledProvisionScriptFragments()) {
+		fragments = append(fragments, fragment.name)
+	}
+	return fragments, nil
+}
+
+func (a *actuator) Reconcile(ctx context.Context, log logr.Logger, osc *extensionsv1alpha1.OperatingSystemConfig) ([]byte, []extensionsv1alpha1.Unit, []extensionsv1alpha1.File, *extensionsv1alpha1.InPlaceUpdatesStatus, error) {
 	switch purpose := osc.Spec.Purpose; purpose {
 	case extensionsv1alpha1.OperatingSystemConfigPurposeProvision:
-		userData, err := a.handleProvisionOSC(ctx, osc)
-		return []byte(userData), nil, nil, nil, err
+		userData, fragments, err := a.handleProvisionOSC(ctx, log, osc)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		log.V(1).Info("Rendered provision script", "fragments", fragments)
+
+		limitedUserData, err := a.limitUserDataSize(ctx, osc, []byte(userData))
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		userDataSize.WithLabelValues(osc.Namespace, osc.Name).Set(float64(len(limitedUserData)))
+		return limitedUserData, nil, nil, nil, nil
 
 	case extensionsv1alpha1.OperatingSystemConfigPurposeReconcile:
-		extensionFiles, err := a.handleReconcileOSC(ctx, osc)
-		return nil, nil, extensionFiles, nil, err
+		extensionUnits, extensionFiles, err := a.handleReconcileOSC(ctx, osc)
+		return nil, extensionUnits, extensionFiles, nil, err
 
 	default:
 		return nil, nil, nil, nil, fmt.Errorf("unknown purpose: %s", purpose)
 	}
 }
 
-func (a *actuator) Delete(_ context.Context, _ logr.Logger, _ *extensionsv1alpha1.OperatingSystemConfig) error {
+func (a *actuator) Delete(_ context.Context, _ logr.Logger, osc *extensionsv1alpha1.OperatingSystemConfig) error {
+	userDataSize.DeleteLabelValues(osc.Namespace, osc.Name)
 	return nil
 }
 
@@ -77,74 +125,238 @@ func (a *actuator) Restore(ctx context.Context, log logr.Logger, osc *extensions
 	return a.Reconcile(ctx, log, osc)
 }
 
-func (a *actuator) handleProvisionOSC(ctx context.Context, osc *extensionsv1alpha1.OperatingSystemConfig) (string, error) {
-	writeFilesToDiskScript, err := operatingsystemconfig.FilesToDiskScript(ctx, a.client, osc.Namespace, osc.Spec.Files)
+// handleProvisionOSC returns the user data of the given OperatingSystemConfig with purpose `provision` and the names of
+// the fragments of its provision script. The user data must be rendered byte-for-byte identically for the same
+// OperatingSystemConfig, otherwise its hash changes between reconciliations and all nodes get rolled.
+func (a *actuator) handleProvisionOSC(ctx context.Context, log logr.Logger, osc *extensionsv1alpha1.OperatingSystemConfig) (string, []string, error) {
+	cluster, err := extensions.GetCluster(ctx, a.client, osc.Namespace)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	suseCHostConfig, memoryOneConfig, cloudConfig, err := provisionConfigurations(osc)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, err := a.provisionScriptData(osc, cluster, suseCHostConfig, memoryOneConfig)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := a.addProvisionFiles(ctx, osc, cluster, data, cloudConfig); err != nil {
+		return "", nil, err
+	}
+
+	render, contentType := renderProvisionScript, mimeTypeShellScript
+	if cloudConfig {
+		render, contentType = renderCloudConfig, mimeTypeCloudConfig
+	}
+
+	userData, fragments, err := render(data, a.disabledProvisionScriptFragments())
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	writeUnitsToDiskScript := operatingsystemconfig.UnitsToDiskScript(osc.Spec.Units)
 
-	script := `#!/bin/bash
-CONTAINERD_CONFIG_PATH=/etc/containerd/config.toml
-if [[ ! -s "${CONTAINERD_CONFIG_PATH}" || $(cat ${CONTAINERD_CONFIG_PATH}) == "# See containerd-config.toml(5) for documentation." ]]; then
-  mkdir -p /etc/containerd
-  containerd config default > "${CONTAINERD_CONFIG_PATH}"
-  chmod 0644 "${CONTAINERD_CONFIG_PATH}"
-fi
+	if osc.Spec.Type == memoryone.OSTypeMemoryOneCHost {
+		additionalParts, err := a.additionalUserDataParts(ctx, osc, cluster, memoryOneConfig)
+		if err != nil {
+			return "", nil, err
+		}
+
+		vsmpConfig, warnings := vsmpConfigString(memoryOneConfig)
+		for _, warning := range append(warnings, memoryonechosthelper.VsmpCatalogWarnings(memoryOneConfig)...) {
+			log.Info("Warning for the vSMP configuration", "warning", warning)
+		}
+
+		userData, err = memoryOneUserData(vsmpConfig, additionalParts, contentType, userData)
+		return userData, fragments, err
+	}
 
-# refer to https://github.com/gardener/gardener-extension-os-suse-chost/tree/master/docs/systemd-units.md
-if systemctl show containerd -p Conflicts | grep -q docker; then
-  cp /usr/lib/systemd/system/containerd.service /etc/systemd/system/containerd.service
-  sed -re 's/Conflicts=(.*)(docker.service|docker)(.*)/Conflicts=\1 \3/g' -i /etc/systemd/system/containerd.service
-fi
+	return userData, fragments, nil
+}
+
+// provisionConfigurations returns the provider config of the given OperatingSystemConfig for its OS type, and whether
+// the user data is rendered as cloud-config.
+func provisionConfigurations(osc *extensionsv1alpha1.OperatingSystemConfig) (*susechostapi.OperatingSystemConfiguration, *memoryonechostapi.OperatingSystemConfiguration, bool, error) {
+	switch osc.Spec.Type {
+	case susechost.OSTypeSuSECHost:
+		suseCHostConfig, err := susechost.Configuration(osc)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return suseCHostConfig, nil, ptr.Deref(suseCHostConfig.UserDataFormat, susechostapi.UserDataFormatScript) == susechostapi.UserDataFormatCloudConfig, nil
+	case memoryone.OSTypeMemoryOneCHost:
+		memoryOneConfig, err := memoryone.Configuration(osc)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return nil, memoryOneConfig, memoryOneConfig != nil && ptr.Deref(memoryOneConfig.UserDataFormat, memoryonechostapi.UserDataFormatScript) == memoryonechostapi.UserDataFormatCloudConfig, nil
+	}
 
-mkdir -p /etc/systemd/system/containerd.service.d
-cat <<EOF > /etc/systemd/system/containerd.service.d/11-exec_config.conf
-[Service]
-ExecStart=
-ExecStart=/usr/sbin/containerd --config=${CONTAINERD_CONFIG_PATH}
-EOF
-chmod 0644 /etc/systemd/system/containerd.service.d/11-exec_config.conf
-` + writeFilesToDiskScript + `
-` + writeUnitsToDiskScript + `
+	return nil, nil, false, nil
+}
+
+// provisionScriptData returns the data the provision script of the given OperatingSystemConfig is rendered with,
+// without the files and units the fragments write to disk, see addProvisionFiles. It suffices to determine the
+// fragments the provision script consists of.
+func (a *actuator) provisionScriptData(osc *extensionsv1alpha1.OperatingSystemConfig, cluster *extensions.Cluster, suseCHostConfig *susechostapi.OperatingSystemConfiguration, memoryOneConfig *memoryonechostapi.OperatingSystemConfiguration) (*provisionScriptData, error) {
+	packagesConfig := packagesConfiguration(suseCHostConfig, memoryOneConfig)
+	packages := packagesToInstall(packagesConfig)
+	data := &provisionScriptData{
+		Packages:                     packages,
+		PackageInstallTimeoutSeconds: int(packageInstallTimeout(packagesConfig).Seconds()),
+		PackageServices:              packageServicesToEnable(packages),
+		DockerFixUnitName:            containerdDockerFixUnitName,
+		ContainerdConfigPath:         containerdConfigPath,
+		ContainerdConfigV2Path:       containerdConfigVersionPath(2),
+		ContainerdConfigV3Path:       containerdConfigVersionPath(3),
+		JournaldUnitName:             journaldUnitName,
+		UnifiedCgroupHierarchy:       unifiedCgroupHierarchy(osc, cluster, suseCHostConfig),
+		Units:                        osc.Spec.Units,
+	}
+
+	repositories, err := a.repositories(suseCHostConfig)
+	if err != nil {
+		return nil, err
+	}
+	data.repositories = repositoriesWithCredentials(repositories, false)
+	data.GPGKeyPaths = gpgKeyPaths(data.repositories)
+
+	// The repositories with credentials are written by gardener-node-agent, hence the packages are installed once their
+	// files are present, see handleReconcileOSC.
+	if !slices.Contains(a.disabledProvisionScriptFragments(), config.ProvisionScriptFragmentRepositories) {
+		awaitedRepositories := repositoriesWithCredentials(repositories, true)
+		data.AwaitedRepositoryFilePaths = repositoryFilePaths(awaitedRepositories)
+		data.AwaitedGPGKeyPaths = gpgKeyPaths(awaitedRepositories)
+	}
 
-until zypper -q install -y wget socat jq nfs-client; [ $? -ne 7 ]; do sleep 1; done
-ln -s /bin/ip /usr/bin/ip
-if [ ! -s /etc/hostname ]; then hostname > /etc/hostname; fi
-systemctl daemon-reload
-ln -s /usr/sbin/containerd-ctr /usr/sbin/ctr
-systemctl enable containerd && systemctl restart containerd
+	return data, nil
+}
 
-systemctl disable docker && systemctl stop docker || echo "No docker service to disable or stop"
+// addProvisionFiles adds the files and units the fragments write to disk to the given data. If the user data is rendered
+// as cloud-config, the files and units are not written by the provision script but by cloud-init.
+func (a *actuator) addProvisionFiles(ctx context.Context, osc *extensionsv1alpha1.OperatingSystemConfig, cluster *extensions.Cluster, data *provisionScriptData, cloudConfig bool) error {
+	if err := a.resolveRepositoryCredentials(ctx, osc, cluster, data.repositories); err != nil {
+		return err
+	}
+	repositoryFiles, err := repositoryFiles(data.repositories)
+	if err != nil {
+		return err
+	}
 
-# Set journald storage to persistent such that logs are written to /var/log instead of /run/log
-if [[ ! -f /etc/systemd/journald.conf.d/10-use-persistent-log-storage.conf ]]; then
-  mkdir -p /etc/systemd/journald.conf.d
-  cat <<EOF > /etc/systemd/journald.conf.d/10-use-persistent-log-storage.conf
-[Journal]
-Storage=persistent
-EOF
-  systemctl restart systemd-journald
-fi
+	// The containerd configuration is rendered for all supported config versions, the provision script installs the
+	// one matching the containerd version of the image.
+	containerdFiles, err := containerdConfigFiles(data.UnifiedCgroupHierarchy)
+	if err != nil {
+		return err
+	}
 
-`
+	// The systemd fixups are the same as the ones returned on reconciliation, so that nodes are provisioned with the
+	// state gardener-node-agent converges them to later on.
+	data.files = map[string]provisionFiles{
+		provisionScriptFragmentFiles:               {files: osc.Spec.Files, units: osc.Spec.Units},
+		config.ProvisionScriptFragmentRepositories: {files: repositoryFiles},
+		config.ProvisionScriptFragmentDocker:       {files: dockerFixupFiles(), units: dockerFixupUnits()},
+		config.ProvisionScriptFragmentContainerd:   {files: containerdFiles, units: containerdFixupUnits()},
+		config.ProvisionScriptFragmentJournald:     {files: journaldFixupFiles()},
+	}
 
-	for _, unit := range osc.Spec.Units {
-		script += fmt.Sprintf(`systemctl enable '%s' && systemctl restart --no-block '%s'
-`, unit.Name, unit.Name)
+	if cloudConfig {
+		// cloud-config carries the content of the files, hence the content of files referencing secrets is inlined.
+		for name, toDisk := range data.files {
+			if toDisk.files, err = a.inlineFileContents(ctx, osc.Namespace, toDisk.files); err != nil {
+				return err
+			}
+			data.files[name] = toDisk
+		}
+		return nil
 	}
 
-	// The provisioning script must run only once.
-	script = operatingsystemconfig.WrapProvisionOSCIntoOneshotScript(script)
+	for _, toDisk := range []struct {
+		fragment    string
+		filesScript *string
+		unitsScript *string
+	}{
+		{provisionScriptFragmentFiles, &data.FilesScript, &data.UnitsScript},
+		{config.ProvisionScriptFragmentRepositories, &data.RepositoryFilesScript, nil},
+		{config.ProvisionScriptFragmentDocker, &data.DockerFixFilesScript, &data.DockerFixUnitsScript},
+		{config.ProvisionScriptFragmentContainerd, &data.ContainerdConfigFilesScript, &data.ContainerdUnitsScript},
+		{config.ProvisionScriptFragmentJournald, &data.JournaldFilesScript, nil},
+	} {
+		files := data.files[toDisk.fragment]
+		if *toDisk.filesScript, err = operatingsystemconfig.FilesToDiskScript(ctx, a.client, osc.Namespace, files.files); err != nil {
+			return err
+		}
+		if toDisk.unitsScript != nil {
+			*toDisk.unitsScript = operatingsystemconfig.UnitsToDiskScript(files.units)
+		}
+	}
 
-	if osc.Spec.Type == memoryone.OSTypeMemoryOneCHost {
-		return wrapIntoMemoryOneHeaderAndFooter(osc, script)
+	return nil
+}
+
+// inlineFileContents returns the given files with the content of files referencing secrets inlined.
+func (a *actuator) inlineFileContents(ctx context.Context, namespace string, files []extensionsv1alpha1.File) ([]extensionsv1alpha1.File, error) {
+	inlined := make([]extensionsv1alpha1.File, 0, len(files))
+
+	for _, file := range files {
+		if file.Content.SecretRef != nil {
+			secret := &corev1.Secret{}
+			if err := a.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: file.Content.SecretRef.Name}, secret); err != nil {
+				return nil, err
+			}
+
+			file.Content = extensionsv1alpha1.FileContent{
+				Inline: &extensionsv1alpha1.FileContentInline{
+					Encoding: "b64",
+					Data:     utils.EncodeBase64(secret.Data[file.Content.SecretRef.DataKey]),
+				},
+				TransmitUnencoded: file.Content.TransmitUnencoded,
+			}
+		}
+		inlined = append(inlined, file)
+	}
+
+	return inlined, nil
+}
+
+// disabledProvisionScriptFragments returns the provision script fragments disabled in the controller configuration.
+func (a *actuator) disabledProvisionScriptFragments() []string {
+	if a.config.ProvisionScript == nil {
+		return nil
+	}
+	return a.config.ProvisionScript.DisabledFragments
+}
+
+// systemdFixups returns the units and files fixing up systemd units of SuSE CHost whose provision script fragments are
+// not disabled.
+func (a *actuator) systemdFixups() ([]extensionsv1alpha1.Unit, []extensionsv1alpha1.File) {
+	var (
+		units []extensionsv1alpha1.Unit
+		files []extensionsv1alpha1.File
+	)
+
+	if !slices.Contains(a.disabledProvisionScriptFragments(), config.ProvisionScriptFragmentContainerd) {
+		units = append(units, containerdFixupUnits()...)
+	}
+	if !slices.Contains(a.disabledProvisionScriptFragments(), config.ProvisionScriptFragmentDocker) {
+		units = append(units, dockerFixupUnits()...)
+		files = append(files, dockerFixupFiles()...)
+	}
+	if !slices.Contains(a.disabledProvisionScriptFragments(), config.ProvisionScriptFragmentJournald) {
+		units = append(units, journaldFixupUnits()...)
+		files = append(files, journaldFixupFiles()...)
 	}
 
-	return script, nil
+	return units, files
 }
 
-func (a *actuator) handleReconcileOSC(ctx context.Context, osc *extensionsv1alpha1.OperatingSystemConfig) ([]extensionsv1alpha1.File, error) {
+func (a *actuator) handleReconcileOSC(ctx context.Context, osc *extensionsv1alpha1.OperatingSystemConfig) ([]extensionsv1alpha1.Unit, []extensionsv1alpha1.File, error) {
+	cluster, err := extensions.GetCluster(ctx, a.client, osc.Namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cluster: %w", err)
+	}
+
 	// enable accepting IPv6 router advertisements so that the interface can obtain a default route
 	// when IP forwarding is enabled (which it is in K8S context)
 	files := []extensionsv1alpha1.File{
@@ -164,38 +376,75 @@ net.ipv6.conf.eth0.accept_ra = 2
 		},
 	}
 
-	failCgroupV1File, err := a.kubeletFailCgroupV1File(ctx, osc)
+	suseCHostConfig, _, _, err := provisionConfigurations(osc)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	failCgroupV1File, err := kubeletFailCgroupV1File(osc, cluster, suseCHostConfig)
+	if err != nil {
+		return nil, nil, err
 	}
 	if failCgroupV1File != nil {
 		files = append(files, *failCgroupV1File)
 	}
 
-	return files, nil
+	// The CRI config (spec.criConfig) is not translated into files on purpose: gardener-node-agent applies it itself by
+	// patching /etc/containerd/config.toml and by writing (and cleaning up) the registry hosts below
+	// /etc/containerd/certs.d, hence files of the extension would be overwritten by it or conflict with it.
+	fixupUnits, fixupFiles := a.systemdFixups()
+	units, files := fixupUnits, append(files, fixupFiles...)
+
+	if !slices.Contains(a.disabledProvisionScriptFragments(), config.ProvisionScriptFragmentRepositories) {
+		allRepositories, err := a.repositories(suseCHostConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		repositories := repositoriesWithCredentials(allRepositories, true)
+		if err := a.resolveRepositoryCredentials(ctx, osc, cluster, repositories); err != nil {
+			return nil, nil, err
+		}
+		repositoryFiles, err := repositoryFiles(repositories)
+		if err != nil {
+			return nil, nil, err
+		}
+		units, files = append(units, gpgKeysImportUnits(repositories)...), append(files, repositoryFiles...)
+	}
+
+	return units, files, nil
 }
 
 // kubeletFailCgroupV1File returns a file that sets KUBELET_EXTRA_ARGS=--fail-cgroupv1=false
-// when the shoot's Kubernetes version is in [1.35, 1.38). Starting with K8s 1.35, kubelet
+// when the Kubernetes version of the worker pool is in [1.35, 1.38). Starting with K8s 1.35, kubelet
 // defaults --fail-cgroupv1 to true and refuses to start on cgroup v1 hosts (KEP-5573).
 // SUSE-CHost still runs cgroup v1, so the kubelet would otherwise fail to start.
-// The flag (and cgroup v1 support) are removed in K8s 1.38, so we must not pass it from then on.
+// The flag (and cgroup v1 support) are removed in K8s 1.38, hence an error is returned for
+// worker pools running cgroup v1 from then on instead of silently dropping the flag.
+// The file is also kept for nodes booting with the unified cgroup hierarchy (cgroup v2): enabling it
+// only affects new nodes, the existing nodes of the worker pool keep running cgroup v1 until they are
+// replaced, and the flag has no effect on cgroup v2 nodes.
 // The file is consumed by kubelet.service via `EnvironmentFile=-/var/lib/kubelet/extra_args`,
 // see github.com/gardener/gardener/pkg/component/extensions/operatingsystemconfig/original/components/kubelet/component.go.
-func (a *actuator) kubeletFailCgroupV1File(ctx context.Context, osc *extensionsv1alpha1.OperatingSystemConfig) (*extensionsv1alpha1.File, error) {
-	cluster, err := extensions.GetCluster(ctx, a.client, osc.Namespace)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get cluster: %w", err)
-	}
+func kubeletFailCgroupV1File(osc *extensionsv1alpha1.OperatingSystemConfig, cluster *extensions.Cluster, suseCHostConfig *susechostapi.OperatingSystemConfiguration) (*extensionsv1alpha1.File, error) {
 	if cluster == nil || cluster.Shoot == nil {
 		return nil, nil
 	}
 
-	version, err := semver.NewVersion(cluster.Shoot.Spec.Kubernetes.Version)
+	worker := workerPool(osc, cluster)
+	version, err := workerPoolKubernetesVersion(cluster, worker)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse kubernetes version %q: %w", cluster.Shoot.Spec.Kubernetes.Version, err)
+		return nil, err
+	}
+	if !version.LessThan(kubeletFailCgroupV1RemovedVersion) {
+		// The nodes of a worker pool are replaced when it is upgraded to a new minor version, hence all of them boot
+		// with the unified cgroup hierarchy if it was enabled before. Nodes which are updated in-place are not replaced.
+		if unifiedCgroupHierarchy(osc, cluster, suseCHostConfig) && (worker == nil || !v1beta1helper.IsUpdateStrategyInPlace(worker.UpdateStrategy)) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("the nodes of worker pool %q run cgroup v1 with the %s OS, but kubelet drops the support for cgroup v1 with Kubernetes %s (KEP-5573), "+
+			"keep the worker pool on a Kubernetes version lower than %s", osc.Labels[v1beta1constants.LabelWorkerPool], osc.Spec.Type, kubeletFailCgroupV1RemovedVersion.Original(), kubeletFailCgroupV1RemovedVersion.Original())
 	}
-	if version.LessThan(kubeletFailCgroupV1MinVersion) || !version.LessThan(kubeletFailCgroupV1RemovedVersion) {
+	if version.LessThan(kubeletFailCgroupV1MinVersion) {
 		return nil, nil
 	}
 
@@ -209,3 +458,47 @@ func (a *actuator) kubeletFailCgroupV1File(ctx context.Context, osc *extensionsv
 		},
 	}, nil
 }
+
+// workerPool returns the worker pool of the Shoot the given OperatingSystemConfig belongs to, or nil if it is not found.
+func workerPool(osc *extensionsv1alpha1.OperatingSystemConfig, cluster *extensions.Cluster) *gardencorev1beta1.Worker {
+	for i, worker := range cluster.Shoot.Spec.Provider.Workers {
+		if worker.Name == osc.Labels[v1beta1constants.LabelWorkerPool] {
+			return &cluster.Shoot.Spec.Provider.Workers[i]
+		}
+	}
+
+	return nil
+}
+
+// workerPoolKubernetesVersion returns the Kubernetes version of the given worker pool, i.e. the version of the worker
+// pool if it overrides it, or the version of the Shoot otherwise.
+func workerPoolKubernetesVersion(cluster *extensions.Cluster, worker *gardencorev1beta1.Worker) (*semver.Version, error) {
+	controlPlaneVersion, err := semver.NewVersion(cluster.Shoot.Spec.Kubernetes.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubernetes version %q: %w", cluster.Shoot.Spec.Kubernetes.Version, err)
+	}
+	if worker == nil {
+		return controlPlaneVersion, nil
+	}
+
+	version, err := v1beta1helper.CalculateEffectiveKubernetesVersion(controlPlaneVersion, worker.Kubernetes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubernetes version of worker pool %q: %w", worker.Name, err)
+	}
+	return version, nil
+}
+
+// unifiedCgroupHierarchy returns true if the nodes of the given OperatingSystemConfig boot with the unified cgroup
+// hierarchy (cgroup v2), see susechost.UnifiedCgroupHierarchyEnabled. This is only supported for suse-chost.
+func unifiedCgroupHierarchy(osc *extensionsv1alpha1.OperatingSystemConfig, cluster *extensions.Cluster, suseCHostConfig *susechostapi.OperatingSystemConfiguration) bool {
+	if osc.Spec.Type != susechost.OSTypeSuSECHost {
+		return false
+	}
+
+	var shootAnnotations map[string]string
+	if cluster != nil && cluster.Shoot != nil {
+		shootAnnotations = cluster.Shoot.Annotations
+	}
+
+	return susechost.UnifiedCgroupHierarchyEnabled(suseCHostConfig, shootAnnotations)
+}
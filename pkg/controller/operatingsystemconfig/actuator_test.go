@@ -5,20 +5,31 @@
 package operatingsystemconfig_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"io"
 	"mime"
 	"mime/multipart"
+	"os"
+	"path"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/gardener/gardener/extensions/pkg/controller/operatingsystemconfig"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
-	"github.com/gardener/gardener/pkg/utils/test"
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -26,12 +37,14 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
-	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
 	memoryonev1alpha1 "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/v1alpha1"
 	. "github.com/gardener/gardener-extension-os-suse-chost/pkg/controller/operatingsystemconfig"
 	"github.com/gardener/gardener-extension-os-suse-chost/pkg/memoryone"
 	"github.com/gardener/gardener-extension-os-suse-chost/pkg/susechost"
+	"github.com/gardener/gardener-extension-os-suse-chost/test/script"
 )
 
 var (
@@ -47,14 +60,103 @@ func init() {
 	testScheme = runtime.NewScheme()
 	runtimeutils.Must(extensionsv1alpha1.AddToScheme(testScheme))
 	runtimeutils.Must(gardencorev1beta1.AddToScheme(testScheme))
+	runtimeutils.Must(corev1.AddToScheme(testScheme))
 }
 
+const (
+	expectedContainerdConfigV2 = `version = 2
+root = "/var/lib/containerd"
+state = "/run/containerd"
+
+[grpc]
+  address = "/run/containerd/containerd.sock"
+
+[plugins]
+  [plugins."io.containerd.grpc.v1.cri"]
+    [plugins."io.containerd.grpc.v1.cri".containerd]
+      snapshotter = "overlayfs"
+      default_runtime_name = "runc"
+      [plugins."io.containerd.grpc.v1.cri".containerd.runtimes]
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+          runtime_type = "io.containerd.runc.v2"
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc.options]
+            SystemdCgroup = false
+    [plugins."io.containerd.grpc.v1.cri".registry]
+      config_path = "/etc/containerd/certs.d"
+`
+
+	expectedContainerdConfigV3 = `version = 3
+root = "/var/lib/containerd"
+state = "/run/containerd"
+
+[grpc]
+  address = "/run/containerd/containerd.sock"
+
+[plugins]
+  [plugins."io.containerd.cri.v1.images"]
+    snapshotter = "overlayfs"
+    [plugins."io.containerd.cri.v1.images".registry]
+      config_path = "/etc/containerd/certs.d"
+  [plugins."io.containerd.cri.v1.runtime"]
+    [plugins."io.containerd.cri.v1.runtime".containerd]
+      default_runtime_name = "runc"
+      [plugins."io.containerd.cri.v1.runtime".containerd.runtimes]
+        [plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runc]
+          runtime_type = "io.containerd.runc.v2"
+          [plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runc.options]
+            SystemdCgroup = false
+`
+	expectedContainerdExecConfigDropIn = `[Service]
+ExecStart=
+ExecStart=/usr/sbin/containerd --config=/etc/containerd/config.toml
+`
+
+	expectedContainerdDockerFixUnit = `[Unit]
+Description=Remove the conflict of containerd with docker and disable docker
+Documentation=https://github.com/gardener/gardener-extension-os-suse-chost/tree/master/docs/systemd-units.md
+Before=containerd.service
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/opt/bin/containerd-docker-fix.sh
+
+[Install]
+WantedBy=multi-user.target
+`
+
+	expectedContainerdDockerFixScript = `#!/bin/bash
+# refer to https://github.com/gardener/gardener-extension-os-suse-chost/tree/master/docs/systemd-units.md
+set -o errexit
+
+VENDOR_UNIT=/usr/lib/systemd/system/containerd.service
+UNIT=/etc/systemd/system/containerd.service
+MARKER="# managed by gardener-extension-os-suse-chost"
+
+if [[ -f "${VENDOR_UNIT}" ]] && grep -qE '^Conflicts=.*docker' "${VENDOR_UNIT}"; then
+  { echo "${MARKER}"; sed -re 's/Conflicts=(.*)(docker.service|docker)(.*)/Conflicts=\1 \3/g' "${VENDOR_UNIT}"; } > "${UNIT}.tmp"
+  if cmp -s "${UNIT}.tmp" "${UNIT}"; then
+    rm -f "${UNIT}.tmp"
+  else
+    mv "${UNIT}.tmp" "${UNIT}"
+    systemctl daemon-reload
+  fi
+elif [[ -f "${UNIT}" ]] && [[ "$(head -n 1 "${UNIT}")" == "${MARKER}" ]]; then
+  rm -f "${UNIT}"
+  systemctl daemon-reload
+fi
+
+if systemctl cat docker.service >/dev/null 2>&1; then
+  systemctl disable --now docker.service
+fi
+`
+)
+
 var _ = Describe("Actuator", func() {
 	var (
 		ctx        = context.TODO()
 		log        = logr.Discard()
 		fakeClient client.Client
-		mgr        manager.Manager
 
 		osc      *extensionsv1alpha1.OperatingSystemConfig
 		actuator operatingsystemconfig.Actuator
@@ -62,12 +164,12 @@ var _ = Describe("Actuator", func() {
 
 	BeforeEach(func() {
 		fakeClient = fakeclient.NewClientBuilder().WithScheme(testScheme).Build()
-		mgr = test.FakeManager{Client: fakeClient}
-		actuator = NewActuator(mgr)
+		actuator = NewActuator(fakeClient, config.ControllerConfiguration{})
 
 		osc = &extensionsv1alpha1.OperatingSystemConfig{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: "shoot--foo--bar",
+				Labels:    map[string]string{"worker.gardener.cloud/pool": "worker-1"},
 			},
 			Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
 				DefaultSpec: extensionsv1alpha1.DefaultSpec{
@@ -87,56 +189,95 @@ if [ -f "/var/lib/osc/provision-osc-applied" ]; then
   exit 0
 fi
 
-CONTAINERD_CONFIG_PATH=/etc/containerd/config.toml
-if [[ ! -s "${CONTAINERD_CONFIG_PATH}" || $(cat ${CONTAINERD_CONFIG_PATH}) == "# See containerd-config.toml(5) for documentation." ]]; then
-  mkdir -p /etc/containerd
-  containerd config default > "${CONTAINERD_CONFIG_PATH}"
-  chmod 0644 "${CONTAINERD_CONFIG_PATH}"
-fi
-
-# refer to https://github.com/gardener/gardener-extension-os-suse-chost/tree/master/docs/systemd-units.md
-if systemctl show containerd -p Conflicts | grep -q docker; then
-  cp /usr/lib/systemd/system/containerd.service /etc/systemd/system/containerd.service
-  sed -re 's/Conflicts=(.*)(docker.service|docker)(.*)/Conflicts=\1 \3/g' -i /etc/systemd/system/containerd.service
-fi
-
-mkdir -p /etc/systemd/system/containerd.service.d
-cat <<EOF > /etc/systemd/system/containerd.service.d/11-exec_config.conf
-[Service]
-ExecStart=
-ExecStart=/usr/sbin/containerd --config=${CONTAINERD_CONFIG_PATH}
-EOF
-chmod 0644 /etc/systemd/system/containerd.service.d/11-exec_config.conf
-
 mkdir -p "/some"
 
 cat << EOF | base64 -d > "/some/file"
 YmFy
 EOF
 
-
 cat << EOF | base64 -d > "/etc/systemd/system/some-unit"
 Zm9v
 EOF
+systemctl daemon-reload
+
+PACKAGES=('wget' 'socat' 'jq' 'nfs-client')
+PACKAGES_INSTALL_DEADLINE=$((SECONDS + 600))
+PACKAGES_INSTALL_BACKOFF=1
+mkdir -p "$(dirname "/var/lib/osc/package-installation-status")"
+packages_install_failed() {
+  echo "$1" >&2
+  echo "$1" > /dev/console 2>/dev/null || true
+  echo "$1" > "/var/lib/osc/package-installation-status"
+  exit 1
+}
+while true; do
+  PACKAGES_INSTALL_EXIT_CODE=0
+  zypper -q install -y "${PACKAGES[@]}" || PACKAGES_INSTALL_EXIT_CODE=$?
+  case "${PACKAGES_INSTALL_EXIT_CODE}" in
+    0)
+      break
+      ;;
+    6|7|8|105)
+      ;;
+    100|101|102|103|106)
+      echo "zypper exited with informational code ${PACKAGES_INSTALL_EXIT_CODE}"
+      break
+      ;;
+    *)
+      packages_install_failed "failed to install packages ${PACKAGES[*]}: zypper exited with code ${PACKAGES_INSTALL_EXIT_CODE}"
+      ;;
+  esac
+  if (( SECONDS + PACKAGES_INSTALL_BACKOFF > PACKAGES_INSTALL_DEADLINE )); then
+    packages_install_failed "failed to install packages ${PACKAGES[*]}: zypper exited with code ${PACKAGES_INSTALL_EXIT_CODE}, giving up after 600 seconds"
+  fi
+  echo "zypper exited with code ${PACKAGES_INSTALL_EXIT_CODE}, retrying in ${PACKAGES_INSTALL_BACKOFF} seconds"
+  sleep "${PACKAGES_INSTALL_BACKOFF}"
+  PACKAGES_INSTALL_BACKOFF=$(( PACKAGES_INSTALL_BACKOFF * 2 > 30 ? 30 : PACKAGES_INSTALL_BACKOFF * 2 ))
+done
+echo "installed packages ${PACKAGES[*]}" > "/var/lib/osc/package-installation-status"
 
-until zypper -q install -y wget socat jq nfs-client; [ $? -ne 7 ]; do sleep 1; done
 ln -s /bin/ip /usr/bin/ip
 if [ ! -s /etc/hostname ]; then hostname > /etc/hostname; fi
+
+mkdir -p "/opt/bin"
+
+` + fileToDiskScript("/opt/bin/containerd-docker-fix.sh", "0755", expectedContainerdDockerFixScript) + `
+
+` + unitFileToDiskScript("/etc/systemd/system/containerd-docker-fix.service", expectedContainerdDockerFixUnit) + `
+systemctl daemon-reload
+systemctl enable 'containerd-docker-fix.service' && systemctl restart 'containerd-docker-fix.service'
+
+mkdir -p "/var/lib/osc/containerd"
+
+` + fileToDiskScript("/var/lib/osc/containerd/config-v2.toml", "0644", expectedContainerdConfigV2) + `
+mkdir -p "/var/lib/osc/containerd"
+
+` + fileToDiskScript("/var/lib/osc/containerd/config-v3.toml", "0644", expectedContainerdConfigV3) + `
+CONTAINERD_CONFIG_PATH='/etc/containerd/config.toml'
+if [[ ! -s "${CONTAINERD_CONFIG_PATH}" || $(cat "${CONTAINERD_CONFIG_PATH}") == "# See containerd-config.toml(5) for documentation." ]]; then
+  CONTAINERD_VERSION=$(containerd --version | awk '{print $3}')
+  CONTAINERD_VERSION="${CONTAINERD_VERSION#v}"
+  if [[ "${CONTAINERD_VERSION%%.*}" == "1" ]]; then
+    CONTAINERD_CONFIG_VERSION_PATH='/var/lib/osc/containerd/config-v2.toml'
+  else
+    CONTAINERD_CONFIG_VERSION_PATH='/var/lib/osc/containerd/config-v3.toml'
+  fi
+  mkdir -p "$(dirname "${CONTAINERD_CONFIG_PATH}")"
+  cp "${CONTAINERD_CONFIG_VERSION_PATH}" "${CONTAINERD_CONFIG_PATH}"
+  chmod 0644 "${CONTAINERD_CONFIG_PATH}"
+fi
+
+mkdir -p "/etc/systemd/system/containerd.service.d"
+
+` + unitFileToDiskScript("/etc/systemd/system/containerd.service.d/11-exec_config.conf", expectedContainerdExecConfigDropIn) + `
 systemctl daemon-reload
 ln -s /usr/sbin/containerd-ctr /usr/sbin/ctr
 systemctl enable containerd && systemctl restart containerd
 
-systemctl disable docker && systemctl stop docker || echo "No docker service to disable or stop"
+mkdir -p "/etc/systemd/journald.conf.d"
 
-# Set journald storage to persistent such that logs are written to /var/log instead of /run/log
-if [[ ! -f /etc/systemd/journald.conf.d/10-use-persistent-log-storage.conf ]]; then
-  mkdir -p /etc/systemd/journald.conf.d
-  cat <<EOF > /etc/systemd/journald.conf.d/10-use-persistent-log-storage.conf
-[Journal]
-Storage=persistent
-EOF
-  systemctl restart systemd-journald
-fi
+` + fileToDiskScript("/etc/systemd/journald.conf.d/10-use-persistent-log-storage.conf", "0644", "[Journal]\nStorage=persistent\n") + `
+systemctl restart 'systemd-journald.service'
 
 systemctl enable 'some-unit' && systemctl restart --no-block 'some-unit'
 
@@ -145,8 +286,19 @@ mkdir -p /var/lib/osc
 touch /var/lib/osc/provision-osc-applied
 `
 
+		When("the cluster resource cannot be found", func() {
+			It("should return an error", func() {
+				_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+				Expect(err).To(MatchError(ContainSubstring("failed to get cluster")))
+			})
+		})
+
 		When("OS type is 'suse-chost'", func() {
 			Describe("#Reconcile", func() {
+				BeforeEach(func() {
+					Expect(createCluster(ctx, fakeClient, osc.Namespace, "1.34.0")).To(Succeed())
+				})
+
 				It("should not return an error", func() {
 					userData, extensionUnits, extensionFiles, inplaceUpdateStatus, err := actuator.Reconcile(ctx, log, osc)
 					Expect(err).NotTo(HaveOccurred())
@@ -156,6 +308,392 @@ touch /var/lib/osc/provision-osc-applied
 					Expect(extensionFiles).To(BeEmpty())
 					Expect(inplaceUpdateStatus).To(BeNil())
 				})
+
+				It("should accept a suse-chost provider config", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration"}`)}
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(userData)).To(Equal(expectedUserData))
+				})
+
+				It("should install the configured packages and enable their services", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","packages":{"add":["open-iscsi"],"remove":["nfs-client"]}}`)}
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(userData)).To(Equal(strings.NewReplacer(
+						"PACKAGES=('wget' 'socat' 'jq' 'nfs-client')", "PACKAGES=('wget' 'socat' 'jq' 'open-iscsi')",
+						"echo \"installed packages ${PACKAGES[*]}\" > \"/var/lib/osc/package-installation-status\"\n", "echo \"installed packages ${PACKAGES[*]}\" > \"/var/lib/osc/package-installation-status\"\nsystemctl enable --now 'iscsid'\n",
+					).Replace(expectedUserData)))
+				})
+
+				It("should not run zypper if no packages are to be installed", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","packages":{"remove":["wget","socat","jq","nfs-client"]}}`)}
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(userData)).NotTo(ContainSubstring("zypper"))
+				})
+
+				It("should use the configured package installation timeout", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","packages":{"installTimeout":"30m"}}`)}
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(userData)).To(And(
+						ContainSubstring("PACKAGES_INSTALL_DEADLINE=$((SECONDS + 1800))\n"),
+						ContainSubstring("giving up after 1800 seconds"),
+					))
+				})
+
+				It("should fail for invalid package names", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","packages":{"add":["foo; reboot"]}}`)}
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`spec.providerConfig.packages.add[0]: Invalid value: "foo; reboot"`)))
+				})
+
+				It("should fail for unknown fields in the suse-chost provider config", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","foo":"bar"}`)}
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`unknown field "foo"`)))
+				})
+			})
+
+			Describe("#Reconcile with zypper repositories", func() {
+				const gpgKey = "-----BEGIN PGP PUBLIC KEY BLOCK-----\nfoo\n-----END PGP PUBLIC KEY BLOCK-----\n"
+
+				BeforeEach(func() {
+					Expect(createClusterForShoot(ctx, fakeClient, osc.Namespace, &gardencorev1beta1.Shoot{
+						Spec: gardencorev1beta1.ShootSpec{
+							Kubernetes: gardencorev1beta1.Kubernetes{Version: "1.34.0"},
+							Resources: []gardencorev1beta1.NamedResourceReference{{
+								Name:        "mirror-credentials",
+								ResourceRef: autoscalingv1.CrossVersionObjectReference{APIVersion: "v1", Kind: "Secret", Name: "mirror-credentials"},
+							}},
+						},
+					})).To(Succeed())
+
+					Expect(fakeClient.Create(ctx, &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "garden", Name: "rmt-credentials"},
+						Data:       map[string][]byte{"username": []byte("rmt-user"), "password": []byte("rmt-password")},
+					})).To(Succeed())
+					Expect(fakeClient.Create(ctx, &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Namespace: osc.Namespace, Name: "ref-mirror-credentials"},
+						Data:       map[string][]byte{"username": []byte("mirror-user"), "password": []byte("mirror-password")},
+					})).To(Succeed())
+
+					actuator = NewActuator(fakeClient, config.ControllerConfiguration{
+						Repositories: []config.Repository{
+							{
+								Name:                 "rmt",
+								URL:                  "https://rmt.example.com/repo/SUSE/Products/SLE-Product-SLES/15-SP5/x86_64/product",
+								GPGKey:               ptr.To(gpgKey),
+								Priority:             ptr.To[int32](90),
+								CredentialsSecretRef: &corev1.SecretReference{Namespace: "garden", Name: "rmt-credentials"},
+							},
+							{
+								Name:     "updates",
+								URL:      "https://updates.example.com/SUSE/Updates/SLE-Product-SLES/15-SP5/x86_64/update",
+								GPGKey:   ptr.To(gpgKey),
+								Priority: ptr.To[int32](95),
+							},
+						},
+					})
+				})
+
+				It("should configure the repositories of the operator without credentials before installing packages", func() {
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					script := string(userData)
+					Expect(script).To(And(
+						ContainSubstring(fileToDiskScript("/etc/zypp/keys/updates.asc", "0644", gpgKey)),
+						ContainSubstring(fileToDiskScript("/etc/zypp/repos.d/updates.repo", "0644", `[updates]
+name=updates
+enabled=1
+autorefresh=1
+baseurl=https://updates.example.com/SUSE/Updates/SLE-Product-SLES/15-SP5/x86_64/update
+type=rpm-md
+gpgcheck=1
+gpgkey=file:///etc/zypp/keys/updates.asc
+priority=95
+`)),
+						ContainSubstring("rpm --import '/etc/zypp/keys/updates.asc'\n"),
+					))
+					Expect(strings.Index(script, "rpm --import")).To(BeNumerically("<", strings.Index(script, "zypper -q install")))
+				})
+
+				It("should not write the repositories with credentials to the user data", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","repositories":[{"name":"mirror","url":"http://mirror.internal/sles","credentialsResourceName":"mirror-credentials"}]}`)}
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					script := string(userData)
+					Expect(script).To(ContainSubstring(`base64 -d > "/etc/zypp/repos.d/updates.repo"`))
+					for _, repository := range []string{"rmt", "mirror"} {
+						Expect(script).NotTo(ContainSubstring(`base64 -d > "/etc/zypp/credentials.d/` + repository + `"`))
+						Expect(script).NotTo(ContainSubstring(`base64 -d > "/etc/zypp/repos.d/` + repository + `.repo"`))
+					}
+					Expect(script).NotTo(ContainSubstring(base64.StdEncoding.EncodeToString([]byte("username=rmt-user\npassword=rmt-password\n"))))
+					Expect(script).NotTo(ContainSubstring(base64.StdEncoding.EncodeToString([]byte("username=mirror-user\npassword=mirror-password\n"))))
+				})
+
+				It("should wait for gardener-node-agent to write the repositories with credentials before installing packages", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","repositories":[{"name":"mirror","url":"http://mirror.internal/sles","credentialsResourceName":"mirror-credentials"}]}`)}
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					script := string(userData)
+					Expect(script).To(And(
+						ContainSubstring("for file in '/etc/zypp/credentials.d/rmt' '/etc/zypp/keys/rmt.asc' '/etc/zypp/repos.d/rmt.repo' '/etc/zypp/credentials.d/mirror' '/etc/zypp/repos.d/mirror.repo'; do\n"),
+						ContainSubstring("rpm --import '/etc/zypp/keys/rmt.asc'\n"),
+					))
+					// gardener-node-agent is started by the units of the OperatingSystemConfig.
+					Expect(strings.Index(script, "systemctl enable 'some-unit'")).To(BeNumerically("<", strings.Index(script, "for file in")))
+					Expect(strings.Index(script, "for file in")).To(BeNumerically("<", strings.Index(script, "zypper -q install")))
+				})
+
+				It("should not wait for the repositories with credentials if the repositories fragment is disabled", func() {
+					actuator = NewActuator(fakeClient, config.ControllerConfiguration{
+						Repositories:    []config.Repository{{Name: "rmt", URL: "https://rmt.example.com", CredentialsSecretRef: &corev1.SecretReference{Namespace: "garden", Name: "rmt-credentials"}}},
+						ProvisionScript: &config.ProvisionScript{DisabledFragments: []string{"repositories"}},
+					})
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					script := string(userData)
+					Expect(script).NotTo(ContainSubstring("/etc/zypp/repos.d/rmt.repo"))
+					Expect(strings.Index(script, "zypper -q install")).To(BeNumerically("<", strings.Index(script, "systemctl enable 'some-unit'")))
+				})
+
+				It("should fail if a repository of the provider config conflicts with one of the operator", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","repositories":[{"name":"rmt","url":"http://mirror.internal/sles"}]}`)}
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`repository "rmt" is already configured by the operator of the extension`)))
+				})
+
+			})
+
+			Describe("#Reconcile with the unified cgroup hierarchy", func() {
+				BeforeEach(func() {
+					Expect(createClusterWithAnnotations(ctx, fakeClient, osc.Namespace, "1.38.0", map[string]string{
+						"suse-chost.os.extensions.gardener.cloud/unified-cgroup-hierarchy": "true",
+					})).To(Succeed())
+				})
+
+				It("should set the kernel parameter and reboot once after the provisioning has been applied", func() {
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(string(userData)).To(ContainSubstring(`# Boot with the unified cgroup hierarchy (cgroup v2)
+if [[ ! -f /sys/fs/cgroup/cgroup.controllers ]]; then
+  if ! grep -qs 'systemd.unified_cgroup_hierarchy=1' /etc/default/grub; then
+    if grep -qs '^GRUB_CMDLINE_LINUX_DEFAULT=' /etc/default/grub; then
+      sed -re 's/^GRUB_CMDLINE_LINUX_DEFAULT="(.*)"$/GRUB_CMDLINE_LINUX_DEFAULT="\1 systemd.unified_cgroup_hierarchy=1"/' -i /etc/default/grub
+    else
+      echo 'GRUB_CMDLINE_LINUX_DEFAULT="systemd.unified_cgroup_hierarchy=1"' >> /etc/default/grub
+    fi
+    if ! grep -q 'systemd.unified_cgroup_hierarchy=1' /etc/default/grub; then
+      echo "Failed to add systemd.unified_cgroup_hierarchy=1 to GRUB_CMDLINE_LINUX_DEFAULT in /etc/default/grub" >&2
+      exit 1
+    fi
+    grub2-mkconfig -o /boot/grub2/grub.cfg
+  fi
+fi
+
+systemctl enable 'some-unit' && systemctl restart --no-block 'some-unit'
+`))
+					Expect(string(userData)).To(HaveSuffix(`
+mkdir -p /var/lib/osc
+touch /var/lib/osc/provision-osc-applied
+
+if [[ ! -f /sys/fs/cgroup/cgroup.controllers && ! -f "/var/lib/osc/unified-cgroup-hierarchy-reboot" ]]; then
+  echo "Rebooting to activate the unified cgroup hierarchy (cgroup v2)..."
+  mkdir -p /var/lib/osc
+  touch "/var/lib/osc/unified-cgroup-hierarchy-reboot"
+  systemctl reboot
+fi
+`))
+				})
+
+				It("should configure the systemd cgroup driver for containerd", func() {
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(string(userData)).To(And(
+						ContainSubstring(fileToDiskScript("/var/lib/osc/containerd/config-v2.toml", "0644", strings.Replace(expectedContainerdConfigV2, "SystemdCgroup = false", "SystemdCgroup = true", 1))),
+						ContainSubstring(fileToDiskScript("/var/lib/osc/containerd/config-v3.toml", "0644", strings.Replace(expectedContainerdConfigV3, "SystemdCgroup = false", "SystemdCgroup = true", 1))),
+					))
+				})
+
+				It("should not configure the unified cgroup hierarchy for memoryone-chost", func() {
+					osc.Spec.Type = memoryone.OSTypeMemoryOneCHost
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(userData)).NotTo(ContainSubstring("unified_cgroup_hierarchy"))
+				})
+			})
+
+			Describe("#Reconcile with the unified cgroup hierarchy in the provider config", func() {
+				It("should set the kernel parameter if the provider config enables it", func() {
+					Expect(createCluster(ctx, fakeClient, osc.Namespace, "1.38.0")).To(Succeed())
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","unifiedCgroupHierarchy":true}`)}
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(userData)).To(And(
+						ContainSubstring("systemd.unified_cgroup_hierarchy=1"),
+						ContainSubstring(`touch "/var/lib/osc/unified-cgroup-hierarchy-reboot"`),
+					))
+				})
+
+				It("should prefer the provider config over the annotation of the Shoot", func() {
+					Expect(createClusterWithAnnotations(ctx, fakeClient, osc.Namespace, "1.34.0", map[string]string{
+						"suse-chost.os.extensions.gardener.cloud/unified-cgroup-hierarchy": "true",
+					})).To(Succeed())
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","unifiedCgroupHierarchy":false}`)}
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(userData)).NotTo(ContainSubstring("unified_cgroup_hierarchy"))
+				})
+			})
+
+			Describe("#Reconcile with a user data size limit", func() {
+				var userDataSize int
+
+				BeforeEach(func() {
+					Expect(createCluster(ctx, fakeClient, osc.Namespace, "1.34.0")).To(Succeed())
+
+					osc.Name = "worker-a"
+					osc.Spec.Files = append(osc.Spec.Files, extensionsv1alpha1.File{Path: "/large/file", Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: strings.Repeat("foo", 1000)}}})
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					userDataSize = len(userData)
+				})
+
+				newActuator := func(maxSize int, compression *config.UserDataCompression) operatingsystemconfig.Actuator {
+					return NewActuator(fakeClient, config.ControllerConfiguration{UserData: &config.UserData{
+						MaxSize:     resource.NewQuantity(int64(maxSize), resource.BinarySI),
+						Compression: compression,
+					}})
+				}
+
+				It("should return the user data if it does not exceed the maximum size", func() {
+					userData, _, _, _, err := newActuator(userDataSize, ptr.To(config.UserDataCompressionGzip)).Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(userData).To(HaveLen(userDataSize))
+					Expect(string(userData)).To(HavePrefix("#!/bin/bash\n"))
+					Expect(userDataSizeMetric(osc)).To(PointTo(BeEquivalentTo(userDataSize)))
+				})
+
+				It("should fail and name the largest files if the user data exceeds the maximum size", func() {
+					_, _, _, _, err := newActuator(userDataSize-1, nil).Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(MatchRegexp(`^user data of %d bytes exceeds the maximum size of %d bytes, largest files: /large/file \(\d+ bytes\), /some/file \(\d+ bytes\)$`, userDataSize, userDataSize-1)))
+				})
+
+				It("should compress the user data if it exceeds the maximum size", func() {
+					actuator = newActuator(userDataSize-1, ptr.To(config.UserDataCompressionGzip))
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(len(userData)).To(BeNumerically("<", userDataSize))
+					Expect(userDataSizeMetric(osc)).To(PointTo(BeEquivalentTo(len(userData))))
+
+					reader, err := gzip.NewReader(bytes.NewReader(userData))
+					Expect(err).NotTo(HaveOccurred())
+					decompressed, err := io.ReadAll(reader)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(decompressed).To(HaveLen(userDataSize))
+					Expect(string(decompressed)).To(ContainSubstring(`cat << EOF | base64 -d > "/large/file"`))
+
+					By("rendering the same user data on every reconciliation")
+					userDataAgain, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(userDataAgain).To(Equal(userData))
+				})
+
+				It("should fail if the compressed user data still exceeds the maximum size", func() {
+					_, _, _, _, err := newActuator(100, ptr.To(config.UserDataCompressionGzip)).Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(MatchRegexp(`^user data of %d bytes \(\d+ bytes compressed\) exceeds the maximum size of 100 bytes, largest files: /large/file`, userDataSize)))
+				})
+
+				It("should not compress the user data of memoryone-chost", func() {
+					osc.Spec.Type = memoryone.OSTypeMemoryOneCHost
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, _, _, _, err = newActuator(len(userData)-1, ptr.To(config.UserDataCompressionGzip)).Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(MatchRegexp(`^user data of %d bytes exceeds the maximum size`, len(userData))))
+				})
+
+				It("should remove the metric on deletion", func() {
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(userDataSizeMetric(osc)).To(PointTo(BeEquivalentTo(userDataSize)))
+
+					Expect(actuator.Delete(ctx, log, osc)).To(Succeed())
+					Expect(userDataSizeMetric(osc)).To(BeNil())
+				})
+			})
+
+			Describe("#Reconcile with cloud-config", func() {
+				BeforeEach(func() {
+					Expect(createCluster(ctx, fakeClient, osc.Namespace, "1.34.0")).To(Succeed())
+					Expect(fakeClient.Create(ctx, &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Namespace: osc.Namespace, Name: "some-secret"},
+						Data:       map[string][]byte{"some-key": []byte("secret")},
+					})).To(Succeed())
+
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","userDataFormat":"cloud-config"}`)}
+					osc.Spec.Files = append(osc.Spec.Files, extensionsv1alpha1.File{
+						Path:        "/some/secret",
+						Permissions: ptr.To[uint32](0600),
+						Content:     extensionsv1alpha1.FileContent{SecretRef: &extensionsv1alpha1.FileContentSecretRef{Name: "some-secret", DataKey: "some-key"}},
+					})
+				})
+
+				It("should write the files with cloud-init and run the provisioning as commands", func() {
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(string(userData)).To(HavePrefix("#cloud-config\n"))
+					Expect(string(userData)).NotTo(ContainSubstring("base64 -d"))
+					Expect(string(userData)).To(And(
+						ContainSubstring("  - path: /some/file\n    content: bar\n"),
+						ContainSubstring("  - path: /some/secret\n    permissions: \"0600\"\n    content: secret\n"),
+						ContainSubstring("  - path: /etc/systemd/system/some-unit\n    content: foo\n"),
+						ContainSubstring("zypper -q install -y"),
+						ContainSubstring("systemctl enable 'some-unit' && systemctl restart --no-block 'some-unit'"),
+					))
+				})
+
+				It("should report the fragments of the provision script", func() {
+					Expect(ProvisionScriptFragments(ctx, fakeClient, config.ControllerConfiguration{}, osc)).To(Equal([]string{"files", "packages", "node", "docker", "containerd", "journald", "units"}))
+				})
+
+				It("should report the fragments of the provision script without reading the referenced secrets", func() {
+					Expect(fakeClient.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: osc.Namespace, Name: "some-secret"}})).To(Succeed())
+
+					Expect(ProvisionScriptFragments(ctx, fakeClient, config.ControllerConfiguration{}, osc)).To(Equal([]string{"files", "packages", "node", "docker", "containerd", "journald", "units"}))
+				})
+
+				It("should fail if a referenced secret is missing", func() {
+					Expect(fakeClient.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: osc.Namespace, Name: "some-secret"}})).To(Succeed())
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`secrets "some-secret" not found`)))
+				})
 			})
 		})
 
@@ -173,6 +711,8 @@ touch /var/lib/osc/provision-osc-applied
 				}
 
 				osc.Spec.Type = memoryone.OSTypeMemoryOneCHost
+
+				Expect(createCluster(ctx, fakeClient, osc.Namespace, "1.34.0")).To(Succeed())
 			})
 
 			When("Legacy fields are used", func() {
@@ -235,9 +775,86 @@ touch /var/lib/osc/provision-osc-applied
 					Expect(extensionFiles).To(BeEmpty())
 					Expect(inplaceUpdateStatus).To(BeNil())
 				})
+
+				It("should render the same user data as the equivalent vsmpConfiguration", func() {
+					memoryOneConfiguration.MemoryTopology = ptr.To("4")
+					memoryOneConfiguration.SystemMemory = ptr.To("8x")
+					Expect(encodeMemoryOneConfigurationIntoOsc(codec, osc, &memoryOneConfiguration)).To(Succeed())
+
+					legacyUserData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					memoryOneConfiguration.MemoryTopology = nil
+					memoryOneConfiguration.SystemMemory = nil
+					memoryOneConfiguration.VsmpConfiguration = map[string]string{"mem_topology": "4", "system_memory": "8x"}
+					Expect(encodeMemoryOneConfigurationIntoOsc(codec, osc, &memoryOneConfiguration)).To(Succeed())
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(userData)).To(Equal(string(legacyUserData)))
+				})
+
+				It("should render the same user data for the v1beta1 version", func() {
+					memoryOneConfiguration.MemoryTopology = ptr.To("4")
+					memoryOneConfiguration.SystemMemory = ptr.To("8x")
+					Expect(encodeMemoryOneConfigurationIntoOsc(codec, osc, &memoryOneConfiguration)).To(Succeed())
+
+					legacyUserData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1beta1","kind":"OperatingSystemConfiguration","memTopology":4,"systemMemoryMultiplier":8}`)}
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(userData)).To(Equal(string(legacyUserData)))
+				})
+			})
+
+			When("the provider config is malformed", func() {
+				It("should fail for unknown fields", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","vsmpConfig":{"mem_topology":"3"}}`)}
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`unknown field "vsmpConfig"`)))
+				})
+
+				It("should fail for duplicate fields", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","systemMemory":"7x","systemMemory":"8x"}`)}
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`duplicate field "systemMemory"`)))
+				})
+
+				It("should fail for the deprecated fields in the v1beta1 version", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1beta1","kind":"OperatingSystemConfiguration","memoryTopology":"3"}`)}
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`unknown field "memoryTopology"`)))
+				})
 			})
 
 			When("MemoryOne configuration map is used", func() {
+				It("should render the same user data on every reconciliation", func() {
+					memoryOneConfiguration.VsmpConfiguration = map[string]string{
+						"foo":            "bar",
+						"abc":            "xyz",
+						"debug_features": "&0xffffff",
+						"pci_dev_filter": "\"00:0a:ce\"",
+						"mem_topology":   "3",
+					}
+
+					Expect(encodeMemoryOneConfigurationIntoOsc(codec, osc, &memoryOneConfiguration)).To(Succeed())
+
+					expectedMemoryOneUserData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					for range 50 {
+						userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(userData).To(Equal(expectedMemoryOneUserData))
+					}
+				})
+
 				It("Should include arbitrary configuration values in vSMP config", func() {
 					memoryOneConfiguration.VsmpConfiguration = map[string]string{
 						"foo": "bar",
@@ -272,22 +889,53 @@ touch /var/lib/osc/provision-osc-applied
 
 					Expect(encodeMemoryOneConfigurationIntoOsc(codec, osc, &memoryOneConfiguration)).To(Succeed())
 
-					userData, extensionUnits, extensionFiles, inplaceUpdateStatus, err := actuator.Reconcile(ctx, log, osc)
-					Expect(err).NotTo(HaveOccurred())
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`spec.providerConfig.vsmpConfiguration[foo]: Invalid value: "bar; foobar: barfoo": value must not contain ';'`)))
+					Expect(userData).To(BeEmpty())
+				})
 
-					vSmpConfig, decodedUserData := decodeVsmpUserData(string(userData))
+				It("Should pass unknown parameters which are not experimental", func() {
+					memoryOneConfiguration.VsmpConfiguration = map[string]string{
+						"mem_topolgy": "3",
+					}
+
+					Expect(encodeMemoryOneConfigurationIntoOsc(codec, osc, &memoryOneConfiguration)).To(Succeed())
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
 
+					vSmpConfig, _ := decodeVsmpUserData(string(userData))
 					Expect(vSmpConfig).To(BeEquivalentTo(map[string]string{
 						"mem_topology":  "2",
 						"system_memory": "6x",
-						"foo":           "bar",
-						"abc":           "xyz",
+						"mem_topolgy":   "3",
 					}))
-					Expect(decodedUserData).To(Equal(expectedUserData))
+				})
 
-					Expect(extensionUnits).To(BeEmpty())
-					Expect(extensionFiles).To(BeEmpty())
-					Expect(inplaceUpdateStatus).To(BeNil())
+				It("Should not allow malformed values of known parameters", func() {
+					memoryOneConfiguration.VsmpConfiguration = map[string]string{
+						"system_memory": "6",
+					}
+
+					Expect(encodeMemoryOneConfigurationIntoOsc(codec, osc, &memoryOneConfiguration)).To(Succeed())
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`spec.providerConfig.vsmpConfiguration[system_memory]: Invalid value: "6": value must be a multiplier, e.g. 6x`)))
+				})
+
+				It("Should not allow line breaks in keys or values", func() {
+					memoryOneConfiguration.VsmpConfiguration = map[string]string{
+						"foo\nbar": "baz",
+						"abc":      "xyz\nsystem_memory=1x",
+					}
+
+					Expect(encodeMemoryOneConfigurationIntoOsc(codec, osc, &memoryOneConfiguration)).To(Succeed())
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(And(
+						ContainSubstring("spec.providerConfig.vsmpConfiguration[abc]"),
+						ContainSubstring("spec.providerConfig.vsmpConfiguration[foo\nbar]"),
+					)))
 				})
 
 				It("Should allow quoted values", func() {
@@ -340,6 +988,94 @@ touch /var/lib/osc/provision-osc-applied
 					Expect(inplaceUpdateStatus).To(BeNil())
 				})
 			})
+
+			When("cloud-config is used", func() {
+				It("should render the provisioning as separate cloud-config part", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","userDataFormat":"cloud-config"}`)}
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					parts := readMimeMultiParts(string(userData))
+					Expect(parts).To(HaveLen(2))
+					Expect(extractVsmpConfiguration(parts[0])).To(Equal(map[string]string{"mem_topology": "2", "system_memory": "6x"}))
+					Expect(parts[1].contentType).To(Equal("text/cloud-config"))
+					Expect(parts[1].content).To(And(
+						HavePrefix("#cloud-config\n"),
+						ContainSubstring("  - path: /some/file\n    content: bar\n"),
+					))
+				})
+			})
+
+			When("packages are configured", func() {
+				It("should install the configured packages", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","packages":{"add":["open-iscsi"],"remove":["nfs-client"],"installTimeout":"90s"}}`)}
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					parts := readMimeMultiParts(string(userData))
+					Expect(parts).To(HaveLen(2))
+					Expect(parts[1].content).To(And(
+						ContainSubstring("PACKAGES=('wget' 'socat' 'jq' 'open-iscsi')\nPACKAGES_INSTALL_DEADLINE=$((SECONDS + 90))\n"),
+						ContainSubstring("systemctl enable --now 'iscsid'\n"),
+					))
+				})
+			})
+
+			When("additional parts are configured", func() {
+				BeforeEach(func() {
+					Expect(fakeClient.Delete(ctx, &extensionsv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: osc.Namespace}})).To(Succeed())
+					Expect(createClusterForShoot(ctx, fakeClient, osc.Namespace, &gardencorev1beta1.Shoot{
+						Spec: gardencorev1beta1.ShootSpec{
+							Kubernetes: gardencorev1beta1.Kubernetes{Version: "1.34.0"},
+							Resources: []gardencorev1beta1.NamedResourceReference{{
+								Name:        "hypervisor-tooling",
+								ResourceRef: autoscalingv1.CrossVersionObjectReference{APIVersion: "v1", Kind: "Secret", Name: "hypervisor-tooling"},
+							}},
+						},
+					})).To(Succeed())
+
+					Expect(fakeClient.Create(ctx, &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Namespace: osc.Namespace, Name: "ref-hypervisor-tooling"},
+						Data:       map[string][]byte{"install.sh": []byte("#!/bin/bash\ninstall-tooling\n")},
+					})).To(Succeed())
+
+					memoryOneConfiguration.AdditionalParts = []memoryonev1alpha1.UserDataPart{
+						{ContentType: "text/cloud-config", Content: ptr.To("#cloud-config\nntp:\n  enabled: true\n")},
+						{ContentType: "text/x-shellscript", SecretRef: &memoryonev1alpha1.UserDataPartSecretRef{ResourceName: "hypervisor-tooling", DataKey: "install.sh"}},
+					}
+					Expect(encodeMemoryOneConfigurationIntoOsc(codec, osc, &memoryOneConfiguration)).To(Succeed())
+				})
+
+				It("should add the parts in the given order before the provision script", func() {
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					parts := readMimeMultiParts(string(userData))
+					Expect(parts).To(HaveLen(4))
+					Expect(extractVsmpConfiguration(parts[0])).To(Equal(map[string]string{"mem_topology": "2", "system_memory": "6x"}))
+					Expect(parts[1]).To(Equal(multiPart{contentType: "text/cloud-config", params: map[string]string{}, content: "#cloud-config\nntp:\n  enabled: true\n"}))
+					Expect(parts[2]).To(Equal(multiPart{contentType: "text/x-shellscript", params: map[string]string{}, content: "#!/bin/bash\ninstall-tooling\n"}))
+					Expect(extractUserdata(parts[3])).To(Equal(expectedUserData))
+				})
+
+				It("should fail if the referenced secret does not contain the data key", func() {
+					memoryOneConfiguration.AdditionalParts[1].SecretRef.DataKey = "foo"
+					Expect(encodeMemoryOneConfigurationIntoOsc(codec, osc, &memoryOneConfiguration)).To(Succeed())
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`secret shoot--foo--bar/ref-hypervisor-tooling of additional part 1 does not contain key "foo"`)))
+				})
+
+				It("should fail if the resource is not referenced by the Shoot", func() {
+					memoryOneConfiguration.AdditionalParts[1].SecretRef.ResourceName = "foo"
+					Expect(encodeMemoryOneConfigurationIntoOsc(codec, osc, &memoryOneConfiguration)).To(Succeed())
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`resource "foo" of additional part 1 not found in the Shoot's resources`)))
+				})
+			})
 		})
 	})
 
@@ -355,13 +1091,68 @@ touch /var/lib/osc/provision-osc-applied
 				})
 
 				It("should not return an error", func() {
-					userData, extensionUnits, _, _, err := actuator.Reconcile(ctx, log, osc)
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(userData).To(BeEmpty())
-					Expect(extensionUnits).To(BeEmpty())
 				})
 
+				It("should leave the CRI config to gardener-node-agent", func() {
+					osc.Spec.CRIConfig = &extensionsv1alpha1.CRIConfig{
+						Name:         extensionsv1alpha1.CRINameContainerD,
+						CgroupDriver: ptr.To(extensionsv1alpha1.CgroupDriverSystemd),
+						Containerd: &extensionsv1alpha1.ContainerdConfig{
+							SandboxImage: "registry.k8s.io/pause:3.10",
+							Registries:   []extensionsv1alpha1.RegistryConfig{{Upstream: "docker.io", Server: ptr.To("https://registry-1.docker.io")}},
+						},
+					}
+
+					_, _, extensionFiles, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(extensionFiles).NotTo(ContainElement(HaveField("Path", HavePrefix("/etc/containerd/"))))
+				})
+
+				DescribeTable("should deploy the systemd unit fixups",
+					func(osType string) {
+						osc.Spec.Type = osType
+
+						_, extensionUnits, extensionFiles, _, err := actuator.Reconcile(ctx, log, osc)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(extensionUnits).To(ConsistOf(
+							extensionsv1alpha1.Unit{
+								Name:    "containerd.service",
+								DropIns: []extensionsv1alpha1.DropIn{{Name: "11-exec_config.conf", Content: expectedContainerdExecConfigDropIn}},
+							},
+							extensionsv1alpha1.Unit{
+								Name:      "containerd-docker-fix.service",
+								Enable:    ptr.To(true),
+								Content:   ptr.To(expectedContainerdDockerFixUnit),
+								FilePaths: []string{"/opt/bin/containerd-docker-fix.sh"},
+							},
+							extensionsv1alpha1.Unit{
+								Name:      "systemd-journald.service",
+								FilePaths: []string{"/etc/systemd/journald.conf.d/10-use-persistent-log-storage.conf"},
+							},
+						))
+						Expect(extensionFiles).To(ContainElements(
+							extensionsv1alpha1.File{
+								Path:        "/opt/bin/containerd-docker-fix.sh",
+								Permissions: ptr.To[uint32](0755),
+								Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: expectedContainerdDockerFixScript}},
+							},
+							extensionsv1alpha1.File{
+								Path:        "/etc/systemd/journald.conf.d/10-use-persistent-log-storage.conf",
+								Permissions: ptr.To[uint32](0644),
+								Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: "[Journal]\nStorage=persistent\n"}},
+							},
+						))
+					},
+					Entry("suse-chost", susechost.OSTypeSuSECHost),
+					Entry("memoryone-chost", memoryone.OSTypeMemoryOneCHost),
+				)
+
 				It("should deploy a sysctl file to configure IPv6 router advertisements", func() {
 					_, _, extensionFiles, _, err := actuator.Reconcile(ctx, log, osc)
 					Expect(err).NotTo(HaveOccurred())
@@ -373,10 +1164,11 @@ net.ipv6.conf.all.accept_ra = 2
 net.ipv6.conf.eth0.accept_ra = 2
 `
 
-					Expect(extensionFiles).To(HaveLen(1))
-					Expect(extensionFiles[0].Path).To(Equal("/etc/sysctl.d/98-enable-ipv6-ra.conf"))
-					Expect(extensionFiles[0].Permissions).To(Equal(ptr.To(uint32(0644))))
-					Expect(extensionFiles[0].Content.Inline.Data).To(Equal(sysctl_content))
+					Expect(extensionFiles).To(ContainElement(extensionsv1alpha1.File{
+						Path:        "/etc/sysctl.d/98-enable-ipv6-ra.conf",
+						Permissions: ptr.To(uint32(0644)),
+						Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: sysctl_content}},
+					}))
 				})
 
 				It("should not deploy the kubelet --fail-cgroupv1 extra_args file", func() {
@@ -411,12 +1203,46 @@ net.ipv6.conf.eth0.accept_ra = 2
 				})
 			})
 
-			Context("when the shoot's Kubernetes version is >= 1.38", func() {
+			Context("when the shoot's Kubernetes version is >= 1.35 and < 1.38 and the unified cgroup hierarchy is enabled", func() {
 				BeforeEach(func() {
-					Expect(createCluster(ctx, fakeClient, osc.Namespace, "1.38.0")).To(Succeed())
+					Expect(createClusterWithAnnotations(ctx, fakeClient, osc.Namespace, "1.35.0", map[string]string{
+						"suse-chost.os.extensions.gardener.cloud/unified-cgroup-hierarchy": "true",
+					})).To(Succeed())
 				})
 
-				It("should not deploy the kubelet --fail-cgroupv1 extra_args file", func() {
+				It("should keep the kubelet --fail-cgroupv1 extra_args file for the nodes which still run cgroup v1", func() {
+					_, _, extensionFiles, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(extensionFiles).To(ContainElement(HaveField("Path", "/var/lib/kubelet/extra_args")))
+				})
+
+				It("should still deploy the kubelet --fail-cgroupv1 extra_args file for memoryone-chost", func() {
+					osc.Spec.Type = memoryone.OSTypeMemoryOneCHost
+
+					_, _, extensionFiles, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(extensionFiles).To(ContainElement(HaveField("Path", "/var/lib/kubelet/extra_args")))
+				})
+			})
+
+			Context("when the shoot's Kubernetes version is >= 1.38", func() {
+				DescribeTable("should fail for worker pools running cgroup v1",
+					func(osType string) {
+						Expect(createCluster(ctx, fakeClient, osc.Namespace, "1.38.0")).To(Succeed())
+						osc.Spec.Type = osType
+
+						_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+						Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf(`the nodes of worker pool "worker-1" run cgroup v1 with the %s OS, but kubelet drops the support for cgroup v1 with Kubernetes 1.38.0`, osType))))
+					},
+					Entry("suse-chost", susechost.OSTypeSuSECHost),
+					Entry("memoryone-chost", memoryone.OSTypeMemoryOneCHost),
+				)
+
+				It("should not deploy the kubelet --fail-cgroupv1 extra_args file if the unified cgroup hierarchy is enabled", func() {
+					Expect(createClusterWithAnnotations(ctx, fakeClient, osc.Namespace, "1.38.0", map[string]string{
+						"suse-chost.os.extensions.gardener.cloud/unified-cgroup-hierarchy": "true",
+					})).To(Succeed())
+
 					_, _, extensionFiles, _, err := actuator.Reconcile(ctx, log, osc)
 					Expect(err).NotTo(HaveOccurred())
 
@@ -424,6 +1250,198 @@ net.ipv6.conf.eth0.accept_ra = 2
 						Expect(f.Path).NotTo(Equal("/var/lib/kubelet/extra_args"))
 					}
 				})
+
+				It("should not deploy the kubelet --fail-cgroupv1 extra_args file if the provider config enables the unified cgroup hierarchy", func() {
+					Expect(createCluster(ctx, fakeClient, osc.Namespace, "1.38.0")).To(Succeed())
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","unifiedCgroupHierarchy":true}`)}
+
+					_, _, extensionFiles, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(extensionFiles).NotTo(ContainElement(HaveField("Path", "/var/lib/kubelet/extra_args")))
+				})
+
+				It("should fail if the provider config disables the unified cgroup hierarchy despite the annotation of the Shoot", func() {
+					Expect(createClusterWithAnnotations(ctx, fakeClient, osc.Namespace, "1.38.0", map[string]string{
+						"suse-chost.os.extensions.gardener.cloud/unified-cgroup-hierarchy": "true",
+					})).To(Succeed())
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","unifiedCgroupHierarchy":false}`)}
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`the nodes of worker pool "worker-1" run cgroup v1`)))
+				})
+
+				It("should fail for worker pools updated in-place even if the unified cgroup hierarchy is enabled", func() {
+					Expect(createClusterForShoot(ctx, fakeClient, osc.Namespace, &gardencorev1beta1.Shoot{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{"suse-chost.os.extensions.gardener.cloud/unified-cgroup-hierarchy": "true"},
+						},
+						Spec: gardencorev1beta1.ShootSpec{
+							Kubernetes: gardencorev1beta1.Kubernetes{Version: "1.38.0"},
+							Provider: gardencorev1beta1.Provider{Workers: []gardencorev1beta1.Worker{
+								{Name: "worker-1", UpdateStrategy: ptr.To(gardencorev1beta1.AutoInPlaceUpdate)},
+							}},
+						},
+					})).To(Succeed())
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`the nodes of worker pool "worker-1" run cgroup v1`)))
+				})
+
+				It("should deploy the kubelet --fail-cgroupv1 extra_args file if the worker pool is kept on an older version", func() {
+					Expect(createClusterForShoot(ctx, fakeClient, osc.Namespace, &gardencorev1beta1.Shoot{
+						Spec: gardencorev1beta1.ShootSpec{
+							Kubernetes: gardencorev1beta1.Kubernetes{Version: "1.38.0"},
+							Provider: gardencorev1beta1.Provider{Workers: []gardencorev1beta1.Worker{
+								{Name: "worker-1", Kubernetes: &gardencorev1beta1.WorkerKubernetes{Version: ptr.To("1.37.2")}},
+							}},
+						},
+					})).To(Succeed())
+
+					_, _, extensionFiles, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(extensionFiles).To(ContainElement(HaveField("Path", "/var/lib/kubelet/extra_args")))
+				})
+			})
+
+			Context("when repositories with credentials are configured", func() {
+				const gpgKey = "-----BEGIN PGP PUBLIC KEY BLOCK-----\nfoo\n-----END PGP PUBLIC KEY BLOCK-----\n"
+
+				BeforeEach(func() {
+					Expect(createClusterForShoot(ctx, fakeClient, osc.Namespace, &gardencorev1beta1.Shoot{
+						Spec: gardencorev1beta1.ShootSpec{
+							Kubernetes: gardencorev1beta1.Kubernetes{Version: "1.34.0"},
+							Resources: []gardencorev1beta1.NamedResourceReference{{
+								Name:        "mirror-credentials",
+								ResourceRef: autoscalingv1.CrossVersionObjectReference{APIVersion: "v1", Kind: "Secret", Name: "mirror-credentials"},
+							}},
+						},
+					})).To(Succeed())
+					Expect(fakeClient.Create(ctx, &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "garden", Name: "rmt-credentials"},
+						Data:       map[string][]byte{"username": []byte("rmt-user"), "password": []byte("rmt-password")},
+					})).To(Succeed())
+					Expect(fakeClient.Create(ctx, &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Namespace: osc.Namespace, Name: "ref-mirror-credentials"},
+						Data:       map[string][]byte{"username": []byte("mirror-user"), "password": []byte("mirror-password")},
+					})).To(Succeed())
+
+					actuator = NewActuator(fakeClient, config.ControllerConfiguration{
+						Repositories: []config.Repository{
+							{
+								Name:                 "rmt",
+								URL:                  "https://rmt.example.com/repo/SUSE/Products/SLE-Product-SLES/15-SP5/x86_64/product",
+								GPGKey:               ptr.To(gpgKey),
+								CredentialsSecretRef: &corev1.SecretReference{Namespace: "garden", Name: "rmt-credentials"},
+							},
+							{
+								Name: "updates",
+								URL:  "https://updates.example.com/SUSE/Updates/SLE-Product-SLES/15-SP5/x86_64/update",
+							},
+						},
+					})
+				})
+
+				It("should deploy the repositories with credentials and import their GPG keys", func() {
+					_, extensionUnits, extensionFiles, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(extensionUnits).To(ContainElement(extensionsv1alpha1.Unit{
+						Name:   "zypper-gpg-keys-import.service",
+						Enable: ptr.To(true),
+						Content: ptr.To(`[Unit]
+Description=Import the GPG keys of the zypper repositories with credentials
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/usr/bin/rpm --import /etc/zypp/keys/rmt.asc
+
+[Install]
+WantedBy=multi-user.target
+`),
+						FilePaths: []string{"/etc/zypp/keys/rmt.asc"},
+					}))
+					Expect(extensionFiles).To(ContainElements(
+						extensionsv1alpha1.File{
+							Path:        "/etc/zypp/credentials.d/rmt",
+							Permissions: ptr.To[uint32](0600),
+							Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: "username=rmt-user\npassword=rmt-password\n"}},
+						},
+						extensionsv1alpha1.File{
+							Path:        "/etc/zypp/keys/rmt.asc",
+							Permissions: ptr.To[uint32](0644),
+							Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: gpgKey}},
+						},
+						extensionsv1alpha1.File{
+							Path:        "/etc/zypp/repos.d/rmt.repo",
+							Permissions: ptr.To[uint32](0644),
+							Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: `[rmt]
+name=rmt
+enabled=1
+autorefresh=1
+baseurl=https://rmt.example.com/repo/SUSE/Products/SLE-Product-SLES/15-SP5/x86_64/product?credentials=rmt
+type=rpm-md
+gpgcheck=1
+gpgkey=file:///etc/zypp/keys/rmt.asc
+`}},
+						},
+					))
+					Expect(extensionFiles).NotTo(ContainElement(HaveField("Path", "/etc/zypp/repos.d/updates.repo")))
+				})
+
+				It("should deploy the repositories of the provider config with credentials from the Shoot's resources", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","repositories":[{"name":"mirror","url":"http://mirror.internal/sles","credentialsResourceName":"mirror-credentials"},{"name":"public","url":"http://public.internal/sles"}]}`)}
+
+					_, _, extensionFiles, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(extensionFiles).To(ContainElements(
+						extensionsv1alpha1.File{
+							Path:        "/etc/zypp/credentials.d/mirror",
+							Permissions: ptr.To[uint32](0600),
+							Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: "username=mirror-user\npassword=mirror-password\n"}},
+						},
+						extensionsv1alpha1.File{
+							Path:        "/etc/zypp/repos.d/mirror.repo",
+							Permissions: ptr.To[uint32](0644),
+							Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: `[mirror]
+name=mirror
+enabled=1
+autorefresh=1
+baseurl=http://mirror.internal/sles?credentials=mirror
+type=rpm-md
+gpgcheck=1
+`}},
+						},
+					))
+					Expect(extensionFiles).NotTo(ContainElement(HaveField("Path", "/etc/zypp/repos.d/public.repo")))
+				})
+
+				It("should fail if the credentials resource is not referenced by the Shoot", func() {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","repositories":[{"name":"mirror","url":"http://mirror.internal/sles","credentialsResourceName":"foo"}]}`)}
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`credentials resource "foo" for repository "mirror" not found in the Shoot's resources`)))
+				})
+
+				It("should not deploy the repositories if the repositories fragment is disabled", func() {
+					actuator = NewActuator(fakeClient, config.ControllerConfiguration{
+						Repositories:    []config.Repository{{Name: "rmt", URL: "https://rmt.example.com", CredentialsSecretRef: &corev1.SecretReference{Namespace: "garden", Name: "rmt-credentials"}}},
+						ProvisionScript: &config.ProvisionScript{DisabledFragments: []string{"repositories"}},
+					})
+
+					_, extensionUnits, extensionFiles, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(extensionUnits).NotTo(ContainElement(HaveField("Name", "zypper-gpg-keys-import.service")))
+					Expect(extensionFiles).NotTo(ContainElement(HaveField("Path", "/etc/zypp/credentials.d/rmt")))
+				})
+
+				It("should fail if the credentials secret of the operator is missing", func() {
+					Expect(fakeClient.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "garden", Name: "rmt-credentials"}})).To(Succeed())
+
+					_, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).To(MatchError(ContainSubstring(`failed to get credentials secret garden/rmt-credentials for repository "rmt"`)))
+				})
 			})
 
 			Context("when the cluster resource cannot be found", func() {
@@ -434,6 +1452,379 @@ net.ipv6.conf.eth0.accept_ra = 2
 			})
 		})
 	})
+
+	Describe("generated scripts", func() {
+		const (
+			suseCHostProviderConfig = `{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","packages":{"add":["open-iscsi","multipath-tools"],"remove":["wget"],"installTimeout":"5m"},"repositories":[{"name":"mirror","url":"http://mirror.internal/sles","gpgKey":"-----BEGIN PGP PUBLIC KEY BLOCK-----\nfoo\n-----END PGP PUBLIC KEY BLOCK-----\n","priority":10}]}`
+			memoryOneProviderConfig = `{"apiVersion":"memoryone-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","memoryTopology":"3;debug_features=&0xffffffff","vsmpConfiguration":{"pci_dev_filter":"\"00:0a:ce\""}}`
+		)
+
+		var entries []TableEntry
+		for _, osType := range []string{susechost.OSTypeSuSECHost, memoryone.OSTypeMemoryOneCHost} {
+			for _, kubernetesVersion := range []string{"1.34.0", "1.35.0", "1.38.0"} {
+				for _, unifiedCgroupHierarchy := range []bool{false, true} {
+					// worker pools running cgroup v1 are not supported with Kubernetes 1.38 anymore
+					if kubernetesVersion == "1.38.0" && (osType != susechost.OSTypeSuSECHost || !unifiedCgroupHierarchy) {
+						continue
+					}
+
+					providerConfigs := map[string]string{"without provider config": ""}
+					if osType == susechost.OSTypeSuSECHost {
+						providerConfigs["with provider config"] = suseCHostProviderConfig
+					} else {
+						providerConfigs["with provider config"] = memoryOneProviderConfig
+					}
+
+					for description, providerConfig := range providerConfigs {
+						entries = append(entries, Entry(fmt.Sprintf("%s, Kubernetes %s, unified cgroup hierarchy %t, %s", osType, kubernetesVersion, unifiedCgroupHierarchy, description),
+							osType, kubernetesVersion, unifiedCgroupHierarchy, providerConfig))
+					}
+				}
+			}
+		}
+
+		DescribeTable("should pass the static checks",
+			func(osType, kubernetesVersion string, unifiedCgroupHierarchy bool, providerConfig string) {
+				Expect(createClusterWithAnnotations(ctx, fakeClient, osc.Namespace, kubernetesVersion, map[string]string{
+					"suse-chost.os.extensions.gardener.cloud/unified-cgroup-hierarchy": strconv.FormatBool(unifiedCgroupHierarchy),
+				})).To(Succeed())
+
+				osc.Spec.Type = osType
+				if providerConfig != "" {
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(providerConfig)}
+				}
+				osc.Spec.CRIConfig = &extensionsv1alpha1.CRIConfig{
+					Name:       extensionsv1alpha1.CRINameContainerD,
+					Containerd: &extensionsv1alpha1.ContainerdConfig{Registries: []extensionsv1alpha1.RegistryConfig{{Upstream: "docker.io", Server: ptr.To("https://registry-1.docker.io")}}},
+				}
+				osc.Spec.Units = append(osc.Spec.Units,
+					extensionsv1alpha1.Unit{Name: "stopped.service", Content: ptr.To("[Unit]"), Command: ptr.To(extensionsv1alpha1.CommandStop)},
+					extensionsv1alpha1.Unit{Name: "disabled.service", Content: ptr.To("[Unit]"), Enable: ptr.To(false)},
+					extensionsv1alpha1.Unit{Name: "vendor.service", DropIns: []extensionsv1alpha1.DropIn{{Name: "10-foo.conf", Content: "[Service]"}}},
+				)
+
+				userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+
+				provisionScript := string(userData)
+				if osType == memoryone.OSTypeMemoryOneCHost {
+					_, provisionScript = decodeVsmpUserData(provisionScript)
+				}
+				Expect(script.Check(provisionScript)).To(Succeed(), provisionScript)
+
+				osc.Spec.Purpose = extensionsv1alpha1.OperatingSystemConfigPurposeReconcile
+				_, _, extensionFiles, _, err := actuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, file := range extensionFiles {
+					if data := file.Content.Inline.Data; strings.HasPrefix(data, "#!/bin/bash") {
+						Expect(script.Check(data)).To(Succeed(), file.Path)
+					}
+				}
+			},
+			entries,
+		)
+	})
+	Describe("provision script in a sandbox", func() {
+		var sandbox *script.Sandbox
+
+		BeforeEach(func() {
+			var err error
+			sandbox, err = script.NewSandbox(GinkgoT().TempDir(), nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		run := func(userData string) string {
+			GinkgoHelper()
+			output, err := sandbox.Run(ctx, userData)
+			Expect(err).NotTo(HaveOccurred(), string(output))
+			return string(output)
+		}
+
+		readFile := func(path string) string {
+			GinkgoHelper()
+			data, err := sandbox.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			return data
+		}
+
+		commands := func() []string {
+			GinkgoHelper()
+			commands, err := sandbox.Commands()
+			Expect(err).NotTo(HaveOccurred())
+			return commands
+		}
+
+		expectedCommands := []string{
+			"systemctl daemon-reload",
+			"zypper -q install -y wget socat jq nfs-client",
+			"ln -s /bin/ip /usr/bin/ip",
+			"hostname",
+			"systemctl daemon-reload",
+			"systemctl enable containerd-docker-fix.service",
+			"systemctl restart containerd-docker-fix.service",
+			"containerd --version",
+			"systemctl daemon-reload",
+			"ln -s /usr/sbin/containerd-ctr /usr/sbin/ctr",
+			"systemctl enable containerd",
+			"systemctl restart containerd",
+			"systemctl restart systemd-journald.service",
+			"systemctl enable some-unit",
+			"systemctl restart --no-block some-unit",
+		}
+
+		When("OS type is 'suse-chost'", func() {
+			BeforeEach(func() {
+				Expect(createCluster(ctx, fakeClient, osc.Namespace, "1.34.0")).To(Succeed())
+			})
+
+			It("should provision the node", func() {
+				userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+
+				run(string(userData))
+
+				Expect(commands()).To(Equal(expectedCommands))
+				Expect(readFile("/some/file")).To(Equal("bar"))
+				Expect(readFile("/etc/systemd/system/some-unit")).To(Equal("foo"))
+				Expect(readFile("/etc/containerd/config.toml")).To(Equal(expectedContainerdConfigV2))
+				Expect(readFile("/etc/systemd/system/containerd.service.d/11-exec_config.conf")).To(Equal(expectedContainerdExecConfigDropIn))
+				Expect(readFile("/etc/systemd/system/containerd-docker-fix.service")).To(Equal(expectedContainerdDockerFixUnit))
+				Expect(readFile("/opt/bin/containerd-docker-fix.sh")).To(Equal(expectedContainerdDockerFixScript))
+				Expect(os.Stat(sandbox.Path("/opt/bin/containerd-docker-fix.sh"))).To(HaveField("Mode()", os.FileMode(0755)))
+				Expect(readFile("/etc/systemd/journald.conf.d/10-use-persistent-log-storage.conf")).To(Equal("[Journal]\nStorage=persistent\n"))
+				Expect(readFile("/etc/hostname")).To(Equal("node-1\n"))
+				Expect(readFile("/var/lib/osc/package-installation-status")).To(Equal("installed packages wget socat jq nfs-client\n"))
+				Expect(sandbox.Path("/var/lib/osc/provision-osc-applied")).To(BeAnExistingFile())
+			})
+
+			It("should provision the node with cloud-config", func() {
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","userDataFormat":"cloud-config"}`)}
+
+				userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+
+				cloudInitScript, err := script.CloudInitScript(string(userData))
+				Expect(err).NotTo(HaveOccurred())
+				run(cloudInitScript)
+
+				Expect(commands()).To(Equal(expectedCommands))
+				Expect(readFile("/some/file")).To(Equal("bar"))
+				Expect(readFile("/etc/systemd/system/some-unit")).To(Equal("foo"))
+				Expect(readFile("/etc/containerd/config.toml")).To(Equal(expectedContainerdConfigV2))
+				Expect(readFile("/opt/bin/containerd-docker-fix.sh")).To(Equal(expectedContainerdDockerFixScript))
+				Expect(readFile("/etc/hostname")).To(Equal("node-1\n"))
+				Expect(sandbox.Path("/var/lib/osc/provision-osc-applied")).To(BeAnExistingFile())
+			})
+
+			It("should exit early when running a second time", func() {
+				userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+
+				run(string(userData))
+				Expect(os.WriteFile(sandbox.Path("/some/file"), []byte("changed"), 0600)).To(Succeed())
+
+				Expect(run(string(userData))).To(Equal("Provision OSC already applied, exiting...\n"))
+				Expect(commands()).To(Equal(expectedCommands))
+				Expect(readFile("/some/file")).To(Equal("changed"))
+			})
+
+			It("should install the containerd configuration for containerd 2", func() {
+				var err error
+				sandbox, err = script.NewSandbox(GinkgoT().TempDir(), map[string]string{
+					"containerd": `echo "containerd github.com/containerd/containerd/v2 v2.0.2 c507a0257ea6462fbd6f5ba4f5c74facb04021f4"`,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+
+				run(string(userData))
+				Expect(readFile("/etc/containerd/config.toml")).To(Equal(expectedContainerdConfigV3))
+			})
+
+			It("should not overwrite an existing containerd configuration", func() {
+				Expect(os.MkdirAll(sandbox.Path("/etc/containerd"), 0755)).To(Succeed())
+				Expect(os.WriteFile(sandbox.Path("/etc/containerd/config.toml"), []byte("version = 2\n"), 0600)).To(Succeed())
+
+				userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+
+				run(string(userData))
+				Expect(readFile("/etc/containerd/config.toml")).To(Equal("version = 2\n"))
+				Expect(commands()).NotTo(ContainElement("containerd --version"))
+			})
+
+			It("should fail and report the failure if the packages cannot be installed", func() {
+				var err error
+				sandbox, err = script.NewSandbox(GinkgoT().TempDir(), map[string]string{"zypper": "exit 7"})
+				Expect(err).NotTo(HaveOccurred())
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","packages":{"installTimeout":"1s"}}`)}
+
+				userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+
+				output, err := sandbox.Run(ctx, string(userData))
+				Expect(err).To(MatchError("exit status 1"))
+				Expect(string(output)).To(ContainSubstring("failed to install packages wget socat jq nfs-client: zypper exited with code 7, giving up after 1 seconds"))
+				Expect(readFile("/var/lib/osc/package-installation-status")).To(Equal("failed to install packages wget socat jq nfs-client: zypper exited with code 7, giving up after 1 seconds\n"))
+				Expect(commands()).To(Equal([]string{"systemctl daemon-reload", "zypper -q install -y wget socat jq nfs-client", "zypper -q install -y wget socat jq nfs-client"}))
+				Expect(sandbox.Path("/var/lib/osc/provision-osc-applied")).NotTo(BeAnExistingFile())
+			})
+
+			Context("with repositories with credentials", func() {
+				BeforeEach(func() {
+					actuator = NewActuator(fakeClient, config.ControllerConfiguration{
+						Repositories: []config.Repository{{Name: "rmt", URL: "https://rmt.example.com", CredentialsSecretRef: &corev1.SecretReference{Namespace: "garden", Name: "rmt-credentials"}}},
+					})
+					osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"suse-chost.os.extensions.gardener.cloud/v1alpha1","kind":"OperatingSystemConfiguration","packages":{"installTimeout":"1s"}}`)}
+				})
+
+				It("should install the packages once gardener-node-agent has written the repositories", func() {
+					for _, file := range []string{"/etc/zypp/credentials.d/rmt", "/etc/zypp/repos.d/rmt.repo"} {
+						Expect(os.MkdirAll(sandbox.Path(path.Dir(file)), 0755)).To(Succeed())
+						Expect(os.WriteFile(sandbox.Path(file), []byte("foo"), 0600)).To(Succeed())
+					}
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					run(string(userData))
+					Expect(commands()).To(ContainElement("zypper -q install -y wget socat jq nfs-client"))
+					Expect(readFile("/var/lib/osc/package-installation-status")).To(Equal("installed packages wget socat jq nfs-client\n"))
+				})
+
+				It("should fail if gardener-node-agent does not write the repositories in time", func() {
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					output, err := sandbox.Run(ctx, string(userData))
+					Expect(err).To(MatchError("exit status 1"))
+					// The sandbox rewrites the path of the awaited file.
+					Expect(string(output)).To(And(
+						ContainSubstring("failed to install packages wget socat jq nfs-client: "),
+						ContainSubstring("/etc/zypp/credentials.d/rmt has not been written by gardener-node-agent within 1 seconds"),
+					))
+					Expect(commands()).NotTo(ContainElement(HavePrefix("zypper")))
+					Expect(sandbox.Path("/var/lib/osc/provision-osc-applied")).NotTo(BeAnExistingFile())
+				})
+			})
+
+			DescribeTable("should fail right away if zypper exits with a non-transient error",
+				func(exitCode int) {
+					var err error
+					sandbox, err = script.NewSandbox(GinkgoT().TempDir(), map[string]string{"zypper": fmt.Sprintf("exit %d", exitCode)})
+					Expect(err).NotTo(HaveOccurred())
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					expectedError := fmt.Sprintf("failed to install packages wget socat jq nfs-client: zypper exited with code %d\n", exitCode)
+					output, err := sandbox.Run(ctx, string(userData))
+					Expect(err).To(MatchError("exit status 1"))
+					Expect(string(output)).To(ContainSubstring(expectedError))
+					Expect(readFile("/var/lib/osc/package-installation-status")).To(Equal(expectedError))
+					Expect(commands()).To(Equal([]string{"systemctl daemon-reload", "zypper -q install -y wget socat jq nfs-client"}))
+					Expect(sandbox.Path("/var/lib/osc/provision-osc-applied")).NotTo(BeAnExistingFile())
+				},
+				Entry("error in the arguments", 4),
+				Entry("capability not found", 104),
+				Entry("RPM scriptlet failed", 107),
+			)
+
+			DescribeTable("should continue if zypper exits with an informational code",
+				func(exitCode int) {
+					var err error
+					sandbox, err = script.NewSandbox(GinkgoT().TempDir(), map[string]string{"zypper": fmt.Sprintf("exit %d", exitCode)})
+					Expect(err).NotTo(HaveOccurred())
+
+					userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(run(string(userData))).To(ContainSubstring(fmt.Sprintf("zypper exited with informational code %d\n", exitCode)))
+					Expect(commands()).To(Equal(expectedCommands))
+					Expect(readFile("/var/lib/osc/package-installation-status")).To(Equal("installed packages wget socat jq nfs-client\n"))
+					Expect(sandbox.Path("/var/lib/osc/provision-osc-applied")).To(BeAnExistingFile())
+				},
+				Entry("update needed", 100),
+				Entry("restart needed", 103),
+				Entry("repositories skipped", 106),
+			)
+		})
+
+		When("the unified cgroup hierarchy is enabled", func() {
+			BeforeEach(func() {
+				Expect(createClusterWithAnnotations(ctx, fakeClient, osc.Namespace, "1.38.0", map[string]string{
+					"suse-chost.os.extensions.gardener.cloud/unified-cgroup-hierarchy": "true",
+				})).To(Succeed())
+				Expect(os.MkdirAll(sandbox.Path("/etc/default"), 0755)).To(Succeed())
+				Expect(os.WriteFile(sandbox.Path("/etc/default/grub"), []byte("GRUB_CMDLINE_LINUX_DEFAULT=\"quiet\"\n"), 0600)).To(Succeed())
+			})
+
+			It("should set the kernel parameter and reboot only once", func() {
+				userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+
+				run(string(userData))
+				Expect(readFile("/etc/default/grub")).To(Equal("GRUB_CMDLINE_LINUX_DEFAULT=\"quiet systemd.unified_cgroup_hierarchy=1\"\n"))
+				Expect(commands()).To(ContainElement("grub2-mkconfig -o /boot/grub2/grub.cfg"))
+				Expect(commands()).To(HaveExactElements(append(slices.Clone(expectedCommands[:len(expectedCommands)-2]),
+					"grub2-mkconfig -o /boot/grub2/grub.cfg",
+					"systemctl enable some-unit",
+					"systemctl restart --no-block some-unit",
+					"systemctl reboot",
+				)))
+
+				// The kernel parameter did not take effect after the reboot.
+				run(string(userData))
+				Expect(commands()).To(HaveLen(len(expectedCommands) + 2))
+			})
+
+			It("should add the kernel parameter if the boot loader configuration does not set any", func() {
+				Expect(os.WriteFile(sandbox.Path("/etc/default/grub"), []byte("GRUB_TIMEOUT=8\n"), 0600)).To(Succeed())
+
+				userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+
+				run(string(userData))
+				Expect(readFile("/etc/default/grub")).To(Equal("GRUB_TIMEOUT=8\nGRUB_CMDLINE_LINUX_DEFAULT=\"systemd.unified_cgroup_hierarchy=1\"\n"))
+				Expect(commands()).To(ContainElements("grub2-mkconfig -o /boot/grub2/grub.cfg", "systemctl reboot"))
+			})
+
+			It("should fail instead of rebooting if the kernel parameter cannot be added", func() {
+				Expect(os.WriteFile(sandbox.Path("/etc/default/grub"), []byte("GRUB_CMDLINE_LINUX_DEFAULT='quiet'\n"), 0600)).To(Succeed())
+
+				userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+
+				output, err := sandbox.Run(ctx, string(userData))
+				Expect(err).To(MatchError("exit status 1"))
+				Expect(string(output)).To(ContainSubstring("Failed to add systemd.unified_cgroup_hierarchy=1 to GRUB_CMDLINE_LINUX_DEFAULT in /etc/default/grub"))
+				Expect(readFile("/etc/default/grub")).To(Equal("GRUB_CMDLINE_LINUX_DEFAULT='quiet'\n"))
+				Expect(commands()).NotTo(ContainElement("grub2-mkconfig -o /boot/grub2/grub.cfg"))
+				Expect(commands()).NotTo(ContainElement("systemctl reboot"))
+				Expect(sandbox.Path("/var/lib/osc/provision-osc-applied")).NotTo(BeAnExistingFile())
+			})
+		})
+
+		When("OS type is 'memoryone-chost'", func() {
+			BeforeEach(func() {
+				Expect(createCluster(ctx, fakeClient, osc.Namespace, "1.34.0")).To(Succeed())
+				osc.Spec.Type = memoryone.OSTypeMemoryOneCHost
+			})
+
+			It("should provision the node", func() {
+				userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, provisionScript := decodeVsmpUserData(string(userData))
+				run(provisionScript)
+
+				Expect(commands()).To(Equal(expectedCommands))
+				Expect(sandbox.Path("/var/lib/osc/provision-osc-applied")).To(BeAnExistingFile())
+			})
+		})
+	})
 })
 
 type multiPart struct {
@@ -527,17 +1918,53 @@ func encodeMemoryOneConfigurationIntoOsc(codec runtime.Codec, osc *extensionsv1a
 	return nil
 }
 
+// userDataSizeMetric returns the value of the user data size metric of the given OperatingSystemConfig, or nil if there
+// is none.
+func userDataSizeMetric(osc *extensionsv1alpha1.OperatingSystemConfig) *float64 {
+	GinkgoHelper()
+
+	families, err := metrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != "suse_chost_user_data_size_bytes" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["namespace"] == osc.Namespace && labels["name"] == osc.Name {
+				return ptr.To(metric.GetGauge().GetValue())
+			}
+		}
+	}
+
+	return nil
+}
+
 func createCluster(ctx context.Context, c client.Client, name, kubernetesVersion string) error {
-	shoot := &gardencorev1beta1.Shoot{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: gardencorev1beta1.SchemeGroupVersion.String(),
-			Kind:       "Shoot",
+	return createClusterWithAnnotations(ctx, c, name, kubernetesVersion, nil)
+}
+
+func createClusterWithAnnotations(ctx context.Context, c client.Client, name, kubernetesVersion string, annotations map[string]string) error {
+	return createClusterForShoot(ctx, c, name, &gardencorev1beta1.Shoot{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: annotations,
 		},
 		Spec: gardencorev1beta1.ShootSpec{
 			Kubernetes: gardencorev1beta1.Kubernetes{
 				Version: kubernetesVersion,
 			},
 		},
+	})
+}
+
+func createClusterForShoot(ctx context.Context, c client.Client, name string, shoot *gardencorev1beta1.Shoot) error {
+	shoot.TypeMeta = metav1.TypeMeta{
+		APIVersion: gardencorev1beta1.SchemeGroupVersion.String(),
+		Kind:       "Shoot",
 	}
 	shootRaw, err := json.Marshal(shoot)
 	if err != nil {
@@ -553,3 +1980,14 @@ func createCluster(ctx context.Context, c client.Client, name, kubernetesVersion
 	}
 	return c.Create(ctx, cluster)
 }
+
+// unitFileToDiskScript returns the snippet written by operatingsystemconfig.UnitsToDiskScript for the given unit or
+// drop-in file.
+func unitFileToDiskScript(path, content string) string {
+	return fmt.Sprintf("cat << EOF | base64 -d > %q\n%s\nEOF", path, base64.StdEncoding.EncodeToString([]byte(content)))
+}
+
+// fileToDiskScript returns the snippet written by operatingsystemconfig.FilesToDiskScript for the given file.
+func fileToDiskScript(path, permissions, content string) string {
+	return fmt.Sprintf("cat << EOF | base64 -d > %q\n%s\nEOF\nchmod %q %q", path, base64.StdEncoding.EncodeToString([]byte(content)), permissions, path)
+}
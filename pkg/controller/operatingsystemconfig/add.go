@@ -12,6 +12,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
 	"github.com/gardener/gardener-extension-os-suse-chost/pkg/memoryone"
 	"github.com/gardener/gardener-extension-os-suse-chost/pkg/susechost"
 )
@@ -27,13 +28,15 @@ type AddOptions struct {
 	IgnoreOperationAnnotation bool
 	// ExtensionClasses defines the extension classes this controller is responsible for.
 	ExtensionClasses []extensionsv1alpha1.ExtensionClass
+	// Config is the controller configuration.
+	Config config.ControllerConfiguration
 }
 
 // AddToManagerWithOptions adds a controller with the given Options to the given manager.
 // The opts.Reconciler is being set with a newly instantiated actuator.
 func AddToManagerWithOptions(ctx context.Context, mgr manager.Manager, opts AddOptions) error {
 	return operatingsystemconfig.Add(mgr, operatingsystemconfig.AddArgs{
-		Actuator:          NewActuator(mgr),
+		Actuator:          NewActuator(mgr.GetClient(), opts.Config),
 		Predicates:        operatingsystemconfig.DefaultPredicates(ctx, mgr, opts.IgnoreOperationAnnotation),
 		Types:             []string{susechost.OSTypeSuSECHost, memoryone.OSTypeMemoryOneCHost},
 		ControllerOptions: opts.Controller,
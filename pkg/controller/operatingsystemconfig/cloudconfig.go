@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"unicode/utf8"
+
+	extensionsv1alpha1helper "github.com/gardener/gardener/pkg/api/extensions/v1alpha1/helper"
+	"go.yaml.in/yaml/v3"
+)
+
+// cloudConfigHeader is the first line of user data in the cloud-config format.
+const cloudConfigHeader = "#cloud-config\n"
+
+// cloudConfig is the part of the cloud-config format of cloud-init used for the user data.
+type cloudConfig struct {
+	WriteFiles []cloudConfigFile `yaml:"write_files,omitempty"`
+	RunCmd     []any             `yaml:"runcmd,omitempty"`
+}
+
+// cloudConfigFile is an entry of `write_files`.
+type cloudConfigFile struct {
+	Path        string `yaml:"path"`
+	Permissions string `yaml:"permissions,omitempty"`
+	Encoding    string `yaml:"encoding,omitempty"`
+	Content     string `yaml:"content"`
+}
+
+// renderCloudConfig renders the user data as cloud-config from all fragments which are included for the given data and
+// not disabled. cloud-init writes the files and units of the fragments with `write_files` before it runs the fragments
+// as `runcmd` entries. It returns the cloud-config and the names of the fragments it consists of.
+func renderCloudConfig(data *provisionScriptData, disabledFragments []string) (string, []string, error) {
+	var (
+		// cloud-init runs the `runcmd` entries in a shell script. It must stop at the first failing fragment, e.g. if the
+		// packages cannot be installed, so that the node does not join the cluster and cloud-init reports the failure.
+		config       = &cloudConfig{RunCmd: []any{"set -e"}}
+		afterApplied []any
+		fragments    []string
+	)
+
+	for _, fragment := range includedProvisionScriptFragments(data, disabledFragments) {
+		out, err := renderProvisionScriptFragment(fragment.name, data)
+		if err != nil {
+			return "", nil, err
+		}
+
+		files, err := cloudConfigFiles(data.files[fragment.name])
+		if err != nil {
+			return "", nil, err
+		}
+		config.WriteFiles = append(config.WriteFiles, files...)
+
+		if out != "" {
+			command := []string{"/bin/bash", "-c", out}
+			if fragment.afterApplied {
+				afterApplied = append(afterApplied, command)
+			} else {
+				config.RunCmd = append(config.RunCmd, command)
+			}
+		}
+		if out != "" || len(files) > 0 {
+			fragments = append(fragments, fragment.name)
+		}
+	}
+
+	// cloud-init runs the `runcmd` entries only once per instance, the marker is written for parity with the provision
+	// script.
+	config.RunCmd = append(config.RunCmd, []string{"mkdir", "-p", "/var/lib/osc"}, []string{"touch", "/var/lib/osc/provision-osc-applied"})
+	config.RunCmd = append(config.RunCmd, afterApplied...)
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return "", nil, fmt.Errorf("failed marshalling cloud-config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", nil, fmt.Errorf("failed marshalling cloud-config: %w", err)
+	}
+
+	return cloudConfigHeader + out.String(), fragments, nil
+}
+
+// cloudConfigFiles returns the `write_files` entries for the given files and units. The content of the files must be
+// inline. Text is written as is to keep the cloud-config readable, everything else base64 encoded.
+func cloudConfigFiles(toDisk provisionFiles) ([]cloudConfigFile, error) {
+	var files []cloudConfigFile
+
+	for _, file := range toDisk.files {
+		if file.Content.Inline == nil {
+			return nil, fmt.Errorf("content of file %q is not inline", file.Path)
+		}
+
+		data, err := extensionsv1alpha1helper.Decode(file.Content.Inline.Encoding, []byte(file.Content.Inline.Data))
+		if err != nil {
+			return nil, fmt.Errorf("failed decoding content of file %q: %w", file.Path, err)
+		}
+
+		cloudConfigFile := cloudConfigFileFor(file.Path, data)
+		if file.Permissions != nil {
+			cloudConfigFile.Permissions = fmt.Sprintf("%04o", *file.Permissions)
+		}
+		files = append(files, cloudConfigFile)
+	}
+
+	for _, unit := range toDisk.units {
+		unitFilePath := path.Join("/", "etc", "systemd", "system", unit.Name)
+
+		if unit.Content != nil {
+			files = append(files, cloudConfigFileFor(unitFilePath, []byte(*unit.Content)))
+		}
+		for _, dropIn := range unit.DropIns {
+			files = append(files, cloudConfigFileFor(path.Join(unitFilePath+".d", dropIn.Name), []byte(dropIn.Content)))
+		}
+	}
+
+	return files, nil
+}
+
+func cloudConfigFileFor(path string, data []byte) cloudConfigFile {
+	if utf8.Valid(data) {
+		return cloudConfigFile{Path: path, Content: string(data)}
+	}
+	return cloudConfigFile{Path: path, Encoding: "b64", Content: base64.StdEncoding.EncodeToString(data)}
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"strings"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.yaml.in/yaml/v3"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
+	"github.com/gardener/gardener-extension-os-suse-chost/test/script"
+)
+
+var _ = Describe("CloudConfig", func() {
+	var data *provisionScriptData
+
+	BeforeEach(func() {
+		data = &provisionScriptData{
+			DockerFixUnitName:      containerdDockerFixUnitName,
+			ContainerdConfigPath:   containerdConfigPath,
+			ContainerdConfigV2Path: containerdConfigVersionPath(2),
+			ContainerdConfigV3Path: containerdConfigVersionPath(3),
+			JournaldUnitName:       journaldUnitName,
+			Units:                  []extensionsv1alpha1.Unit{{Name: "some-unit", Content: ptr.To("foo")}},
+			files: map[string]provisionFiles{
+				provisionScriptFragmentFiles: {
+					files: []extensionsv1alpha1.File{
+						{Path: "/some/file", Permissions: ptr.To[uint32](0600), Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: "bar\n"}}},
+						{Path: "/some/binary", Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Encoding: "b64", Data: "/w=="}}},
+					},
+					units: []extensionsv1alpha1.Unit{{
+						Name:    "some-unit",
+						Content: ptr.To("foo"),
+						DropIns: []extensionsv1alpha1.DropIn{{Name: "10-bar.conf", Content: "bar"}},
+					}},
+				},
+				config.ProvisionScriptFragmentJournald: {files: []extensionsv1alpha1.File{{Path: "/etc/systemd/journald.conf.d/10-storage.conf", Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: "baz"}}}}},
+			},
+		}
+	})
+
+	decode := func(userData string) map[string]any {
+		GinkgoHelper()
+
+		Expect(userData).To(HavePrefix("#cloud-config\n"))
+		cloudConfig := map[string]any{}
+		Expect(yaml.Unmarshal([]byte(userData), &cloudConfig)).To(Succeed())
+		return cloudConfig
+	}
+
+	Describe("#renderCloudConfig", func() {
+		It("should write the files and units of the fragments", func() {
+			userData, _, err := renderCloudConfig(data, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(decode(userData)["write_files"]).To(Equal([]any{
+				map[string]any{"path": "/some/file", "permissions": "0600", "content": "bar\n"},
+				map[string]any{"path": "/some/binary", "encoding": "b64", "content": "/w=="},
+				map[string]any{"path": "/etc/systemd/system/some-unit", "content": "foo"},
+				map[string]any{"path": "/etc/systemd/system/some-unit.d/10-bar.conf", "content": "bar"},
+				map[string]any{"path": "/etc/systemd/journald.conf.d/10-storage.conf", "content": "baz"},
+			}))
+		})
+
+		It("should run the fragments as commands", func() {
+			data.UnifiedCgroupHierarchy = true
+
+			userData, fragments, err := renderCloudConfig(data, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fragments).To(Equal([]string{"files", "node", "docker", "containerd", "journald", "cgroup", "units", "cgroup-reboot"}))
+
+			runCmd := decode(userData)["runcmd"].([]any)
+			Expect(runCmd[0]).To(Equal("set -e"))
+
+			var commands []string
+			for _, command := range runCmd[1:] {
+				var args []string
+				for _, arg := range command.([]any) {
+					args = append(args, arg.(string))
+				}
+
+				if args[0] != "/bin/bash" {
+					commands = append(commands, strings.Join(args, " "))
+					continue
+				}
+
+				Expect(args).To(HaveLen(3))
+				Expect(args[1]).To(Equal("-c"))
+				Expect(script.Check("#!/bin/bash\n" + args[2])).To(Succeed())
+				commands = append(commands, "bash")
+			}
+
+			// The reboot runs after the provisioning has been marked as applied.
+			Expect(commands).To(Equal([]string{"bash", "bash", "bash", "bash", "bash", "bash", "bash", "mkdir -p /var/lib/osc", "touch /var/lib/osc/provision-osc-applied", "bash"}))
+		})
+
+		It("should leave out the files of disabled fragments", func() {
+			userData, fragments, err := renderCloudConfig(data, []string{"journald"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fragments).NotTo(ContainElement("journald"))
+			Expect(userData).NotTo(ContainSubstring("/etc/systemd/journald.conf.d/10-storage.conf"))
+			Expect(userData).NotTo(ContainSubstring(journaldUnitName))
+		})
+
+		It("should fail for files which are not inline", func() {
+			data.files[provisionScriptFragmentFiles] = provisionFiles{files: []extensionsv1alpha1.File{{
+				Path:    "/some/file",
+				Content: extensionsv1alpha1.FileContent{SecretRef: &extensionsv1alpha1.FileContentSecretRef{Name: "foo", DataKey: "bar"}},
+			}}}
+
+			Expect(renderCloudConfig(data, nil)).Error().To(MatchError(`content of file "/some/file" is not inline`))
+		})
+	})
+})
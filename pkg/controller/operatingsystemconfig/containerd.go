@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+
+	"github.com/BurntSushi/toml"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+)
+
+const (
+	containerdConfigPath = "/etc/containerd/config.toml"
+	// containerdConfigsDir contains the rendered containerd configurations for all supported config versions. The
+	// provision script installs the one matching the containerd version of the image as containerdConfigPath.
+	containerdConfigsDir = "/var/lib/osc/containerd"
+
+	containerdRoot        = "/var/lib/containerd"
+	containerdState       = "/run/containerd"
+	containerdAddress     = "/run/containerd/containerd.sock"
+	containerdCertsDir    = "/etc/containerd/certs.d"
+	containerdSnapshotter = "overlayfs"
+	containerdRuntimeName = "runc"
+	containerdRuntimeType = "io.containerd.runc.v2"
+)
+
+// containerdConfigVersions are the supported containerd config versions: version 2 is used by containerd 1.x, version
+// 3 by containerd 2.x.
+var containerdConfigVersions = []int{2, 3}
+
+// containerdConfig is the part of the containerd configuration managed by this extension. Everything else is left to
+// the defaults of containerd, so that the rendered configuration works with all minor versions of a major version.
+type containerdConfig struct {
+	Version int                  `toml:"version"`
+	Root    string               `toml:"root"`
+	State   string               `toml:"state"`
+	GRPC    containerdGRPCConfig `toml:"grpc"`
+	Plugins map[string]any       `toml:"plugins"`
+}
+
+type containerdGRPCConfig struct {
+	Address string `toml:"address"`
+}
+
+type containerdCRIConfigV2 struct {
+	Containerd containerdRuntimesConfig `toml:"containerd"`
+	Registry   containerdRegistryConfig `toml:"registry"`
+}
+
+type containerdCRIImagesConfigV3 struct {
+	Snapshotter string                   `toml:"snapshotter"`
+	Registry    containerdRegistryConfig `toml:"registry"`
+}
+
+type containerdCRIRuntimeConfigV3 struct {
+	Containerd containerdRuntimesConfig `toml:"containerd"`
+}
+
+type containerdRuntimesConfig struct {
+	Snapshotter        string                             `toml:"snapshotter,omitempty"`
+	DefaultRuntimeName string                             `toml:"default_runtime_name"`
+	Runtimes           map[string]containerdRuntimeConfig `toml:"runtimes"`
+}
+
+type containerdRuntimeConfig struct {
+	RuntimeType string                         `toml:"runtime_type"`
+	Options     containerdRuntimeOptionsConfig `toml:"options"`
+}
+
+type containerdRuntimeOptionsConfig struct {
+	SystemdCgroup bool `toml:"SystemdCgroup"`
+}
+
+type containerdRegistryConfig struct {
+	ConfigPath string `toml:"config_path"`
+}
+
+// renderContainerdConfig renders the containerd configuration in the given config version. systemdCgroup configures
+// runc to use the systemd cgroup driver, which is required on nodes running the unified cgroup hierarchy.
+func renderContainerdConfig(version int, systemdCgroup bool) ([]byte, error) {
+	runtimes := containerdRuntimesConfig{
+		DefaultRuntimeName: containerdRuntimeName,
+		Runtimes: map[string]containerdRuntimeConfig{
+			containerdRuntimeName: {
+				RuntimeType: containerdRuntimeType,
+				Options:     containerdRuntimeOptionsConfig{SystemdCgroup: systemdCgroup},
+			},
+		},
+	}
+	registry := containerdRegistryConfig{ConfigPath: containerdCertsDir}
+
+	config := containerdConfig{
+		Version: version,
+		Root:    containerdRoot,
+		State:   containerdState,
+		GRPC:    containerdGRPCConfig{Address: containerdAddress},
+	}
+
+	switch version {
+	case 2:
+		runtimes.Snapshotter = containerdSnapshotter
+		config.Plugins = map[string]any{
+			"io.containerd.grpc.v1.cri": containerdCRIConfigV2{Containerd: runtimes, Registry: registry},
+		}
+	case 3:
+		config.Plugins = map[string]any{
+			"io.containerd.cri.v1.images":  containerdCRIImagesConfigV3{Snapshotter: containerdSnapshotter, Registry: registry},
+			"io.containerd.cri.v1.runtime": containerdCRIRuntimeConfigV3{Containerd: runtimes},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported containerd config version %d", version)
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(config); err != nil {
+		return nil, fmt.Errorf("failed encoding containerd config version %d: %w", version, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// containerdConfigFiles returns the containerd configurations for all supported config versions.
+func containerdConfigFiles(systemdCgroup bool) ([]extensionsv1alpha1.File, error) {
+	var files []extensionsv1alpha1.File
+
+	for _, version := range containerdConfigVersions {
+		data, err := renderContainerdConfig(version, systemdCgroup)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, inlineFile(containerdConfigVersionPath(version), 0644, string(data)))
+	}
+
+	return files, nil
+}
+
+func containerdConfigVersionPath(version int) string {
+	return path.Join(containerdConfigsDir, fmt.Sprintf("config-v%d.toml", version))
+}
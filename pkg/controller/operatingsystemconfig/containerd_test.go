@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"github.com/BurntSushi/toml"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Containerd", func() {
+	Describe("#renderContainerdConfig", func() {
+		// lookup returns the value at the given path of the decoded configuration.
+		lookup := func(config map[string]any, path ...string) any {
+			var value any = config
+			for _, key := range path {
+				m, ok := value.(map[string]any)
+				Expect(ok).To(BeTrue(), "expected a table at %q", key)
+				value = m[key]
+			}
+			return value
+		}
+
+		// The paths are the ones gardener-node-agent patches according to the config version, see
+		// github.com/gardener/gardener/pkg/nodeagent/controller/operatingsystemconfig/containerd_config.go.
+		DescribeTable("should render a configuration with the settings at the paths of the config version",
+			func(version int, systemdCgroup bool, runtimePlugin, imagesPlugin string) {
+				data, err := renderContainerdConfig(version, systemdCgroup)
+				Expect(err).NotTo(HaveOccurred())
+
+				config := map[string]any{}
+				Expect(toml.Unmarshal(data, &config)).To(Succeed())
+
+				Expect(config["version"]).To(BeEquivalentTo(version))
+				Expect(lookup(config, "grpc", "address")).To(Equal("/run/containerd/containerd.sock"))
+				Expect(lookup(config, "plugins", runtimePlugin, "containerd", "default_runtime_name")).To(Equal("runc"))
+				Expect(lookup(config, "plugins", runtimePlugin, "containerd", "runtimes", "runc", "runtime_type")).To(Equal("io.containerd.runc.v2"))
+				Expect(lookup(config, "plugins", runtimePlugin, "containerd", "runtimes", "runc", "options", "SystemdCgroup")).To(Equal(systemdCgroup))
+				Expect(lookup(config, "plugins", imagesPlugin, "registry", "config_path")).To(Equal("/etc/containerd/certs.d"))
+			},
+			Entry("version 2 with cgroupfs", 2, false, "io.containerd.grpc.v1.cri", "io.containerd.grpc.v1.cri"),
+			Entry("version 2 with systemd cgroups", 2, true, "io.containerd.grpc.v1.cri", "io.containerd.grpc.v1.cri"),
+			Entry("version 3 with cgroupfs", 3, false, "io.containerd.cri.v1.runtime", "io.containerd.cri.v1.images"),
+			Entry("version 3 with systemd cgroups", 3, true, "io.containerd.cri.v1.runtime", "io.containerd.cri.v1.images"),
+		)
+
+		It("should render the same configuration every time", func() {
+			first, err := renderContainerdConfig(3, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			for range 10 {
+				Expect(renderContainerdConfig(3, true)).To(Equal(first))
+			}
+		})
+
+		It("should fail for unsupported config versions", func() {
+			_, err := renderContainerdConfig(1, false)
+			Expect(err).To(MatchError("unsupported containerd config version 1"))
+		})
+	})
+
+	Describe("#containerdConfigFiles", func() {
+		It("should return a file for every supported config version", func() {
+			files, err := containerdConfigFiles(false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(files).To(HaveLen(2))
+			Expect(files[0].Path).To(Equal("/var/lib/osc/containerd/config-v2.toml"))
+			Expect(files[1].Path).To(Equal("/var/lib/osc/containerd/config-v3.toml"))
+		})
+	})
+})
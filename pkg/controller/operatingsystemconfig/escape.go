@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"strings"
+	"unicode"
+)
+
+// shellQuote quotes the given value as a single bash word which expands to exactly the given value. Every value
+// interpolated into the provision script must be quoted with it, so that no value can change the structure of the
+// script.
+func shellQuote(s string) string {
+	return `'` + strings.ReplaceAll(s, `'`, `'\''`) + `'`
+}
+
+// shellQuoteAll quotes each of the given values with shellQuote and joins them with spaces.
+func shellQuoteAll(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, shellQuote(v))
+	}
+	return strings.Join(quoted, " ")
+}
+
+// mimeLine returns the given value up to its first line break or other control character, so that it cannot add lines
+// to the body of a MIME part it is interpolated into.
+func mimeLine(s string) string {
+	if i := strings.IndexFunc(s, func(r rune) bool { return unicode.IsControl(r) && r != '\t' }); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	"mvdan.cc/sh/v3/expand"
+	"mvdan.cc/sh/v3/syntax"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost"
+)
+
+var _ = Describe("Escape", func() {
+	DescribeTable("#shellQuote",
+		func(value, expected string) {
+			Expect(shellQuote(value)).To(Equal(expected))
+		},
+		Entry("empty", "", `''`),
+		Entry("word", "kubelet.service", `'kubelet.service'`),
+		Entry("whitespace", "foo bar\tbaz\n", "'foo bar\tbaz\n'"),
+		Entry("expansions", "$(reboot) `reboot` ${HOME}", "'$(reboot) `reboot` ${HOME}'"),
+		Entry("single quotes", "it's", `'it'\''s'`),
+	)
+
+	It("#shellQuoteAll", func() {
+		Expect(shellQuoteAll(nil)).To(BeEmpty())
+		Expect(shellQuoteAll([]string{"wget", "it's"})).To(Equal(`'wget' 'it'\''s'`))
+	})
+
+	DescribeTable("#mimeLine",
+		func(value, expected string) {
+			Expect(mimeLine(value)).To(Equal(expected))
+		},
+		Entry("empty", "", ""),
+		Entry("single line", "\"00:0a:ce\"\t# comment", "\"00:0a:ce\"\t# comment"),
+		Entry("line feed", "6x\n--==BOUNDARY==", "6x"),
+		Entry("carriage return", "6x\rfoo", "6x"),
+		Entry("other control character", "6x\x00foo", "6x"),
+	)
+})
+
+// The fuzz tests below render scripts for arbitrary values and for placeholders and compare the structure of both. The
+// structure is the same if the values can only end up in the words the placeholders end up in.
+
+func FuzzShellQuote(f *testing.F) {
+	for _, seed := range []string{"", "foo", "it's", "'", `\'`, "$(reboot)", "`reboot`", "foo\nreboot", "a\\"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		skipUnrepresentableShellValue(t, value)
+		assertShellStructure(t, func(values ...string) string { return "echo " + shellQuote(values[0]) + "\n" }, value)
+	})
+}
+
+func FuzzUnitCommandsScript(f *testing.F) {
+	f.Add("kubelet.service", true, uint8(0), uint8(0))
+	f.Add("foo'; reboot; '", true, uint8(1), uint8(2))
+	f.Add("foo\nreboot", false, uint8(2), uint8(1))
+	f.Add("$(reboot)", false, uint8(0), uint8(0))
+
+	f.Fuzz(func(t *testing.T, name string, content bool, enable, command uint8) {
+		skipUnrepresentableShellValue(t, name)
+
+		unit := extensionsv1alpha1.Unit{
+			Enable:  []*bool{nil, ptr.To(true), ptr.To(false)}[enable%3],
+			Command: []*extensionsv1alpha1.UnitCommand{nil, ptr.To(extensionsv1alpha1.CommandRestart), ptr.To(extensionsv1alpha1.CommandStop)}[command%3],
+		}
+		if content {
+			unit.Content = ptr.To("[Unit]")
+		}
+
+		assertShellStructure(t, func(values ...string) string {
+			unit.Name = values[0]
+			return renderFragment(t, provisionScriptFragmentUnits, &provisionScriptData{Units: []extensionsv1alpha1.Unit{unit}})
+		}, name)
+	})
+}
+
+func FuzzInstallPackagesScript(f *testing.F) {
+	f.Add("wget", "jq")
+	f.Add("wget'; reboot; '", "$(reboot)")
+	f.Add("foo\n", "bar baz")
+
+	f.Fuzz(func(t *testing.T, first, second string) {
+		skipUnrepresentableShellValue(t, first)
+		skipUnrepresentableShellValue(t, second)
+
+		assertShellStructure(t, func(values ...string) string {
+			return renderFragment(t, config.ProvisionScriptFragmentPackages, &provisionScriptData{Packages: values, PackageInstallTimeoutSeconds: 60})
+		}, first, second)
+	})
+}
+
+func FuzzMemoryOneUserData(f *testing.F) {
+	f.Add("mem_topology", "3", "#!/bin/bash\n")
+	f.Add("foo", "bar\n--==BOUNDARY==--", "#!/bin/bash\n")
+	f.Add("foo\nbar", "baz", "echo\n--==BOUNDARY==\n")
+	f.Add("foo", "bar", "echo\r\n--==BOUNDARY==\r\n")
+
+	f.Fuzz(func(t *testing.T, key, value, script string) {
+		vsmpConfig, _ := vsmpConfigString(&memoryonechost.OperatingSystemConfiguration{VsmpConfiguration: map[string]string{key: value}})
+		if lines := strings.Count(vsmpConfig, "\n"); lines < 2 || lines > 3 {
+			t.Fatalf("vSMP configuration %q for %q=%q has %d lines", vsmpConfig, key, value, lines)
+		}
+
+		userData, err := memoryOneUserData(vsmpConfig, nil, mimeTypeShellScript, script)
+		if err != nil {
+			t.Fatalf("failed to render user data: %v", err)
+		}
+
+		parts, err := parseMultipartUserData(userData)
+		if err != nil {
+			t.Fatalf("failed to read user data %q: %v", userData, err)
+		}
+
+		var bodies []string
+		for _, part := range parts {
+			bodies = append(bodies, part.body)
+		}
+		if !slices.Equal(bodies, []string{vsmpConfig, script}) {
+			t.Fatalf("user data %q has parts %q, expected %q", userData, bodies, []string{vsmpConfig, script})
+		}
+	})
+}
+
+func renderFragment(t *testing.T, name string, data *provisionScriptData) string {
+	t.Helper()
+
+	script, err := renderProvisionScriptFragment(name, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+// skipUnrepresentableShellValue skips values which cannot be part of a bash script at all (NUL), or which cannot be
+// parsed (invalid UTF-8). Values of API objects are always valid UTF-8.
+func skipUnrepresentableShellValue(t *testing.T, value string) {
+	if strings.ContainsRune(value, 0) || !utf8.ValidString(value) {
+		t.Skip()
+	}
+}
+
+// assertShellStructure renders the script with the given values and with placeholders, and fails if the structure of
+// both scripts differs.
+func assertShellStructure(t *testing.T, render func(values ...string) string, values ...string) {
+	t.Helper()
+
+	placeholders := make([]string, 0, len(values))
+	for i := range values {
+		placeholders = append(placeholders, fmt.Sprintf("placeholder-%d", i))
+	}
+
+	expected, err := shellStructure(render(placeholders...))
+	if err != nil {
+		t.Fatalf("failed to parse script rendered with placeholders: %v", err)
+	}
+	for i, token := range expected {
+		if j := slices.Index(placeholders, strings.TrimPrefix(token, "literal:")); j >= 0 {
+			expected[i] = "literal:" + values[j]
+		}
+	}
+
+	script := render(values...)
+	actual, err := shellStructure(script)
+	if err != nil {
+		t.Fatalf("failed to parse script rendered with %q: %v\n%s", values, err, script)
+	}
+	if !slices.Equal(actual, expected) {
+		t.Fatalf("structure of script rendered with %q differs:\n%s", values, script)
+	}
+}
+
+// shellStructure parses the given bash script and returns its nodes in walk order. Words which expand to a single
+// literal field are returned as a single token carrying the field, independent of how they are quoted.
+func shellStructure(script string) ([]string, error) {
+	file, err := syntax.NewParser(syntax.Variant(syntax.LangBash)).Parse(strings.NewReader(script), "")
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		tokens  []string
+		walkErr error
+	)
+	syntax.Walk(file, func(node syntax.Node) bool {
+		if node == nil || walkErr != nil {
+			return false
+		}
+		if word, ok := node.(*syntax.Word); ok && isLiteralWord(word) {
+			fields, err := expand.Fields(nil, word)
+			if err != nil {
+				walkErr = err
+				return false
+			}
+			if len(fields) == 1 {
+				tokens = append(tokens, "literal:"+fields[0])
+			} else {
+				tokens = append(tokens, fmt.Sprintf("fields:%q", fields))
+			}
+			return false
+		}
+		tokens = append(tokens, fmt.Sprintf("%T", node))
+		return true
+	})
+
+	return tokens, walkErr
+}
+
+func isLiteralWord(word *syntax.Word) bool {
+	for _, part := range word.Parts {
+		switch p := part.(type) {
+		case *syntax.Lit:
+		case *syntax.SglQuoted:
+			if p.Dollar {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -5,91 +5,274 @@
 package operatingsystemconfig
 
 import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"maps"
+	"mime"
+	"slices"
 	"strings"
 
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/extensions"
+	corev1 "k8s.io/api/core/v1"
 
-	memoryonechost "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/v1alpha1"
-	"github.com/gardener/gardener-extension-os-suse-chost/pkg/memoryone"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/helper"
 )
 
 const (
-	memoryTopology = "mem_topology"
-	systemMemory   = "system_memory"
+	// mimeTypeVsmp is the content type of the part of the user data containing the vSMP configuration.
+	mimeTypeVsmp = "text/x-vsmp"
+	// mimeTypeShellScript is the content type of the part of the user data containing the provision script.
+	mimeTypeShellScript = "text/x-shellscript"
+	// mimeTypeCloudConfig is the content type of the part of the user data containing the cloud-config.
+	mimeTypeCloudConfig = "text/cloud-config"
+
+	// legacyMIMEBoundary is the fixed boundary the user data had before it could collide with the parts.
+	legacyMIMEBoundary = "==BOUNDARY=="
 )
 
-func wrapIntoMemoryOneHeaderAndFooter(osc *extensionsv1alpha1.OperatingSystemConfig, in string) (string, error) {
-	config, err := memoryone.Configuration(osc)
-	if err != nil {
-		return "", err
+// mimePart is a part of multipart user data.
+type mimePart struct {
+	contentType string
+	params      map[string]string
+	body        string
+}
+
+// memoryOneUserData returns the multipart user data consisting of the given vSMP configuration, the additional parts and
+// the provisioning part of the given content type. cloud-init runs the scripts in the order of the parts, i.e. scripts
+// of additional parts run before the provisioning.
+func memoryOneUserData(vsmpConfig string, additionalParts []mimePart, contentType, provisioning string) (string, error) {
+	parts := []mimePart{{contentType: mimeTypeVsmp, params: map[string]string{"section": "vsmp"}, body: vsmpConfig}}
+	parts = append(parts, additionalParts...)
+	parts = append(parts, mimePart{contentType: contentType, body: provisioning})
+
+	return multipartUserData(parts)
+}
+
+// additionalUserDataParts returns the additional parts of the user data configured in the given memoryone-chost
+// configuration. The content of parts referencing a Secret is read from the resources of the Shoot.
+func (a *actuator) additionalUserDataParts(ctx context.Context, osc *extensionsv1alpha1.OperatingSystemConfig, cluster *extensions.Cluster, config *memoryonechost.OperatingSystemConfiguration) ([]mimePart, error) {
+	if config == nil {
+		return nil, nil
 	}
 
-	memoryOneConfiguration := vsmpConfigString(config)
+	parts := make([]mimePart, 0, len(config.AdditionalParts))
+	for i, part := range config.AdditionalParts {
+		if part.Content != nil {
+			parts = append(parts, mimePart{contentType: part.ContentType, body: *part.Content})
+			continue
+		}
+		if part.SecretRef == nil {
+			return nil, fmt.Errorf("additional part %d has no content", i)
+		}
 
-	out := `Content-Type: multipart/mixed; boundary="==BOUNDARY=="
-MIME-Version: 1.0
---==BOUNDARY==
-Content-Type: text/x-vsmp; section=vsmp
+		resourceName := part.SecretRef.ResourceName
+		if cluster == nil || cluster.Shoot == nil {
+			return nil, fmt.Errorf("cannot resolve resource %q of additional part %d without a Shoot", resourceName, i)
+		}
 
-` + memoryOneConfiguration + `
---==BOUNDARY==
-Content-Type: text/x-shellscript
+		resource := v1beta1helper.GetResourceByName(cluster.Shoot.Spec.Resources, resourceName)
+		if resource == nil {
+			return nil, fmt.Errorf("resource %q of additional part %d not found in the Shoot's resources", resourceName, i)
+		}
 
-` + in + `
---==BOUNDARY==--
-`
+		secret := &corev1.Secret{}
+		if err := extensionscontroller.GetObjectByReference(ctx, a.client, &resource.ResourceRef, osc.Namespace, secret); err != nil {
+			return nil, fmt.Errorf("failed to get secret of additional part %d: %w", i, err)
+		}
 
-	return out, nil
+		content, ok := secret.Data[part.SecretRef.DataKey]
+		if !ok {
+			return nil, fmt.Errorf("secret %s/%s of additional part %d does not contain key %q", secret.Namespace, secret.Name, i, part.SecretRef.DataKey)
+		}
+		parts = append(parts, mimePart{contentType: part.ContentType, body: string(content)})
+	}
+
+	return parts, nil
 }
 
-func vsmpConfigString(config *memoryonechost.OperatingSystemConfiguration) string {
-	var vsmpConfiguration map[string]string
-	var configStringBuilder strings.Builder
+// multipartUserData returns a multipart/mixed document consisting of the given parts. It uses line feeds instead of
+// CRLF, as the user data always did, and the boundary returned by mimeBoundary.
+func multipartUserData(parts []mimePart) (string, error) {
+	bodies := make([]string, 0, len(parts))
+	for _, part := range parts {
+		bodies = append(bodies, part.body)
+	}
+	boundary := mimeBoundary(bodies...)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "Content-Type: %s\nMIME-Version: 1.0\n", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": boundary}))
 
-	if config != nil && config.VsmpConfiguration != nil {
-		vsmpConfiguration = config.VsmpConfiguration
-		// TODO: put stripSemicola down into the StringBuilder-Fprintf and remove this loop once we end support for legacy values
-		// this is required as we do not want to allow injecting key-value pairs with semicola in the new parameter map
-		// but need to retain the previous behaviour for the legacy configuration style
-		for k, v := range vsmpConfiguration {
-			vsmpConfiguration[k] = stripSemicola(v)
+	for _, part := range parts {
+		contentType := mime.FormatMediaType(part.contentType, part.params)
+		if contentType == "" {
+			return "", fmt.Errorf("invalid content type %q of MIME part", part.contentType)
 		}
-	} else {
-		vsmpConfiguration = make(map[string]string, 2)
+
+		// The line break before a delimiter belongs to the delimiter, not to the body of the part.
+		fmt.Fprintf(&out, "--%s\nContent-Type: %s\n\n%s\n", boundary, contentType, part.body)
 	}
+	fmt.Fprintf(&out, "--%s--\n", boundary)
 
-	if _, ok := vsmpConfiguration[memoryTopology]; !ok {
-		vsmpConfiguration[memoryTopology] = "2"
+	return out.String(), nil
+}
+
+// mimeBoundary returns a boundary which no line of the given bodies starts with, so that no body can end its part early
+// or inject parts. It is the legacy boundary unless a body contains it, so that the user data of existing nodes does not
+// change. Otherwise, it is derived from the bodies instead of being random, as the user data must be stable, see
+// handleProvisionOSC.
+func mimeBoundary(bodies ...string) string {
+	if !slices.ContainsFunc(bodies, func(body string) bool { return containsMIMEDelimiter(body, legacyMIMEBoundary) }) {
+		return legacyMIMEBoundary
 	}
 
-	if _, ok := vsmpConfiguration[systemMemory]; !ok {
-		vsmpConfiguration[systemMemory] = "6x"
+	hash := sha256.New()
+	for _, body := range bodies {
+		// Prefix each body with its length, so that different bodies cannot result in the same hash input.
+		fmt.Fprintf(hash, "%d:%s", len(body), body)
 	}
+	sum := hash.Sum(nil)
 
-	// TODO: remove these once the transition to VsmpConfiguration map[string]string is complete
-	if config != nil {
-		if config.SystemMemory != nil {
-			vsmpConfiguration[systemMemory] = *config.SystemMemory
+	for {
+		boundary := "==BOUNDARY-" + hex.EncodeToString(sum[:12]) + "=="
+		if !slices.ContainsFunc(bodies, func(body string) bool { return containsMIMEDelimiter(body, boundary) }) {
+			return boundary
 		}
+		// A body containing the boundary derived from it is next to impossible, rehash to get another one then.
+		next := sha256.Sum256(sum)
+		sum = next[:]
+	}
+}
 
-		if config.MemoryTopology != nil {
-			vsmpConfiguration[memoryTopology] = *config.MemoryTopology
+// containsMIMEDelimiter returns whether a line of the given body starts with the delimiter of the given boundary.
+func containsMIMEDelimiter(body, boundary string) bool {
+	for line := range strings.Lines(body) {
+		if strings.HasPrefix(line, "--"+boundary) {
+			return true
 		}
 	}
-	// end TODO
+	return false
+}
+
+// vsmpConfigString returns the vSMP configuration of the given memoryone-chost configuration in the format the MemoryOne
+// hypervisor reads from the user data. It renders the effective parameters of helper.VsmpConfiguration, except for the
+// deprecated fields which are rendered as they are, including the parameters injected through them, so that the user
+// data of existing nodes does not change, see rawLegacyVsmpValues. It also returns the warnings of
+// helper.VsmpConfiguration about overridden or ignored parameters.
+func vsmpConfigString(config *memoryonechost.OperatingSystemConfiguration) (string, []string) {
+	var configStringBuilder strings.Builder
+
+	vsmpConfiguration, warnings := helper.VsmpConfiguration(config)
 
-	for k, v := range vsmpConfiguration {
-		fmt.Fprintf(&configStringBuilder, "%s=%s\n", stripSemicola(k), v)
+	if _, ok := vsmpConfiguration[helper.VsmpKeyMemoryTopology]; !ok {
+		vsmpConfiguration[helper.VsmpKeyMemoryTopology] = "2"
 	}
 
-	return configStringBuilder.String()
+	if _, ok := vsmpConfiguration[helper.VsmpKeySystemMemory]; !ok {
+		vsmpConfiguration[helper.VsmpKeySystemMemory] = "6x"
+	}
+
+	values := maps.Clone(vsmpConfiguration)
+	for key, value := range rawLegacyVsmpValues(config, vsmpConfiguration) {
+		// The injected parameters are part of the line of the deprecated field.
+		parameters, _ := helper.ParseLegacyValue(key, value)
+		for _, parameter := range parameters {
+			delete(values, parameter.Key)
+		}
+		values[key] = value
+	}
+
+	for _, k := range vsmpConfigKeys(values) {
+		// Keys and values must not contain line breaks, otherwise they could inject lines into the user data.
+		fmt.Fprintf(&configStringBuilder, "%s=%s\n", mimeLine(k), mimeLine(values[k]))
+	}
+
+	return configStringBuilder.String(), warnings
+}
+
+// rawLegacyVsmpValues returns the values of the deprecated fields of the given configuration which can be rendered as
+// they are, keyed by the parameter of the field. This is the case if all parameters of the field are effective, see
+// the given vSMP configuration, and none of the injected ones is also set by `vsmpConfiguration` or the other deprecated
+// field. Otherwise, the line of the field would conflict with the line of another parameter.
+func rawLegacyVsmpValues(config *memoryonechost.OperatingSystemConfiguration, vsmpConfiguration map[string]string) map[string]string {
+	if config == nil {
+		return nil
+	}
+
+	legacyValues := map[string]string{}
+	if config.SystemMemory != nil {
+		legacyValues[helper.VsmpKeySystemMemory] = *config.SystemMemory
+	}
+	if config.MemoryTopology != nil {
+		legacyValues[helper.VsmpKeyMemoryTopology] = *config.MemoryTopology
+	}
+
+	legacyParameters := map[string][]helper.VsmpParameter{}
+	for key, value := range legacyValues {
+		legacyParameters[key], _ = helper.ParseLegacyValue(key, value)
+	}
+
+	setElsewhere := func(key, injectedKey string) bool {
+		if _, ok := config.VsmpConfiguration[injectedKey]; ok {
+			return true
+		}
+		for otherKey, otherParameters := range legacyParameters {
+			if otherKey != key && (otherKey == injectedKey || slices.ContainsFunc(otherParameters, func(parameter helper.VsmpParameter) bool {
+				return parameter.Key == injectedKey
+			})) {
+				return true
+			}
+		}
+		return false
+	}
+
+	rawValues := map[string]string{}
+	for key, parameters := range legacyParameters {
+		// The parameter of the field itself comes last, it is missing if its value is empty.
+		if len(parameters) == 0 || parameters[len(parameters)-1].Key != key {
+			continue
+		}
+
+		if slices.ContainsFunc(parameters, func(parameter helper.VsmpParameter) bool {
+			return vsmpConfiguration[parameter.Key] != parameter.Value || (parameter.Key != key && setElsewhere(key, parameter.Key))
+		}) {
+			continue
+		}
+
+		rawValues[key] = legacyValues[key]
+	}
+
+	return rawValues
+}
+
+// vsmpConfigKeys returns the keys of the given vSMP configuration in the order they are rendered. The order must be
+// stable, see handleProvisionOSC. `mem_topology` and `system_memory` always come first, all other keys follow in lexical order.
+func vsmpConfigKeys(vsmpConfiguration map[string]string) []string {
+	keys := slices.Collect(maps.Keys(vsmpConfiguration))
+
+	slices.SortFunc(keys, func(a, b string) int {
+		if c := cmp.Compare(vsmpKeyPriority(a), vsmpKeyPriority(b)); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+
+	return keys
 }
 
-func stripSemicola(s string) string {
-	before, _, found := strings.Cut(s, ";")
-	if found {
-		return before
+func vsmpKeyPriority(key string) int {
+	switch key {
+	case helper.VsmpKeyMemoryTopology:
+		return 0
+	case helper.VsmpKeySystemMemory:
+		return 1
+	default:
+		return 2
 	}
-	return s
 }
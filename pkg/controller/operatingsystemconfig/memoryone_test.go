@@ -0,0 +1,262 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost"
+)
+
+var _ = Describe("MemoryOne", func() {
+	Describe("#vsmpConfigString", func() {
+		It("should render the defaults if no configuration is given", func() {
+			Expect(vsmpConfigString(nil)).To(Equal("mem_topology=2\nsystem_memory=6x\n"))
+		})
+
+		It("should render mem_topology and system_memory first and all other keys in lexical order", func() {
+			config := &memoryonechost.OperatingSystemConfiguration{
+				VsmpConfiguration: map[string]string{
+					"pci_dev_filter": `"00:0a:ce"`,
+					"system_memory":  "7x",
+					"abc":            "xyz",
+					"mem_topology":   "3",
+					"debug_features": "&0xffffff",
+				},
+			}
+
+			Expect(vsmpConfigString(config)).To(Equal(`mem_topology=3
+system_memory=7x
+abc=xyz
+debug_features=&0xffffff
+pci_dev_filter="00:0a:ce"
+`))
+		})
+
+		It("should render the same configuration byte-for-byte identically", func() {
+			config := &memoryonechost.OperatingSystemConfiguration{
+				MemoryTopology:    ptr.To("4"),
+				VsmpConfiguration: map[string]string{},
+			}
+			for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p"} {
+				config.VsmpConfiguration[key] = key + "-value"
+			}
+
+			expected, _ := vsmpConfigString(config)
+			for range 100 {
+				Expect(vsmpConfigString(config)).To(Equal(expected))
+			}
+		})
+
+		It("should render the legacy fields as they are", func() {
+			config := &memoryonechost.OperatingSystemConfiguration{
+				MemoryTopology:    ptr.To("3;debug_features=&0xffffffff"),
+				SystemMemory:      ptr.To("4x; foo=bar"),
+				VsmpConfiguration: map[string]string{"mem_topology": "2", "abc": "xyz"},
+			}
+
+			vsmpConfig, warnings := vsmpConfigString(config)
+			Expect(vsmpConfig).To(Equal("mem_topology=3;debug_features=&0xffffffff\nsystem_memory=4x; foo=bar\nabc=xyz\n"))
+			Expect(warnings).To(ConsistOf(
+				`memoryTopology: overrides mem_topology="2" with "3"`,
+			))
+		})
+
+		It("should render the effective parameters if the legacy fields conflict with other parameters", func() {
+			config := &memoryonechost.OperatingSystemConfiguration{
+				MemoryTopology:    ptr.To("3;debug_features=&0xffffffff"),
+				SystemMemory:      ptr.To("4x; foo=bar"),
+				VsmpConfiguration: map[string]string{"mem_topology": "2", "debug_features": "&0xff", "foo": "bar"},
+			}
+
+			vsmpConfig, warnings := vsmpConfigString(config)
+			Expect(vsmpConfig).To(Equal("mem_topology=3\nsystem_memory=4x\ndebug_features=&0xffffffff\nfoo=bar\n"))
+			Expect(warnings).To(ConsistOf(
+				`memoryTopology: overrides debug_features="&0xff" with "&0xffffffff"`,
+				`memoryTopology: overrides mem_topology="2" with "3"`,
+			))
+		})
+
+		It("should render the effective parameters if the legacy fields conflict with each other", func() {
+			config := &memoryonechost.OperatingSystemConfiguration{
+				MemoryTopology: ptr.To("3;system_memory=5x"),
+				SystemMemory:   ptr.To("4x;debug_features=&0xff"),
+			}
+
+			vsmpConfig, warnings := vsmpConfigString(config)
+			Expect(vsmpConfig).To(Equal("mem_topology=3\nsystem_memory=5x\ndebug_features=&0xff\n"))
+			Expect(warnings).To(ConsistOf(
+				`memoryTopology: overrides system_memory="4x" with "5x"`,
+			))
+		})
+
+		It("should not render a legacy field with an empty value as it is", func() {
+			config := &memoryonechost.OperatingSystemConfiguration{
+				MemoryTopology:    ptr.To(";foo=bar"),
+				VsmpConfiguration: map[string]string{"mem_topology": "4"},
+			}
+
+			vsmpConfig, _ := vsmpConfigString(config)
+			Expect(vsmpConfig).To(Equal("mem_topology=4\nsystem_memory=6x\nfoo=bar\n"))
+		})
+
+		It("should not mutate the given configuration", func() {
+			config := &memoryonechost.OperatingSystemConfiguration{
+				MemoryTopology: ptr.To("3"),
+				SystemMemory:   ptr.To("7x"),
+				VsmpConfiguration: map[string]string{
+					"foo": "bar",
+				},
+			}
+			original := config.DeepCopy()
+
+			Expect(vsmpConfigString(config)).To(Equal("mem_topology=3\nsystem_memory=7x\nfoo=bar\n"))
+			Expect(config).To(Equal(original))
+		})
+	})
+
+	Describe("#memoryOneUserData", func() {
+		It("should render the vSMP configuration and the provisioning as parts", func() {
+			userData, err := memoryOneUserData("mem_topology=2\nsystem_memory=6x\n", nil, mimeTypeCloudConfig, "#cloud-config\n")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parseMultipartUserData(userData)).To(Equal([]mimePart{
+				{contentType: "text/x-vsmp", params: map[string]string{"section": "vsmp"}, body: "mem_topology=2\nsystem_memory=6x\n"},
+				{contentType: "text/cloud-config", params: map[string]string{}, body: "#cloud-config\n"},
+			}))
+		})
+
+		It("should render the additional parts between the vSMP configuration and the provisioning", func() {
+			userData, err := memoryOneUserData("mem_topology=2\n", []mimePart{
+				{contentType: "text/x-shellscript", body: "#!/bin/bash\necho foo\n"},
+				{contentType: "text/cloud-config", body: "#cloud-config\nbootcmd: []\n"},
+			}, mimeTypeShellScript, "#!/bin/bash\n")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parseMultipartUserData(userData)).To(Equal([]mimePart{
+				{contentType: "text/x-vsmp", params: map[string]string{"section": "vsmp"}, body: "mem_topology=2\n"},
+				{contentType: "text/x-shellscript", params: map[string]string{}, body: "#!/bin/bash\necho foo\n"},
+				{contentType: "text/cloud-config", params: map[string]string{}, body: "#cloud-config\nbootcmd: []\n"},
+				{contentType: "text/x-shellscript", params: map[string]string{}, body: "#!/bin/bash\n"},
+			}))
+		})
+
+		It("should render the user data of existing nodes byte-for-byte identically", func() {
+			Expect(memoryOneUserData("mem_topology=2\nsystem_memory=6x\n", nil, mimeTypeShellScript, "#!/bin/bash\necho foo\n")).To(Equal(`Content-Type: multipart/mixed; boundary="==BOUNDARY=="
+MIME-Version: 1.0
+--==BOUNDARY==
+Content-Type: text/x-vsmp; section=vsmp
+
+mem_topology=2
+system_memory=6x
+
+--==BOUNDARY==
+Content-Type: text/x-shellscript
+
+#!/bin/bash
+echo foo
+
+--==BOUNDARY==--
+`))
+		})
+
+		It("should render the same user data byte-for-byte identically", func() {
+			expected, err := memoryOneUserData("mem_topology=2\n", nil, mimeTypeShellScript, "#!/bin/bash\n")
+			Expect(err).NotTo(HaveOccurred())
+
+			for range 100 {
+				Expect(memoryOneUserData("mem_topology=2\n", nil, mimeTypeShellScript, "#!/bin/bash\n")).To(Equal(expected))
+			}
+		})
+	})
+
+	Describe("#multipartUserData", func() {
+		It("should keep parts containing other boundaries intact", func() {
+			parts := []mimePart{
+				{contentType: "text/x-shellscript", params: map[string]string{}, body: "#!/bin/bash\ncat <<EOF\n--==BOUNDARY==\nContent-Type: text/x-vsmp\n\n--==BOUNDARY==--\nEOF\n"},
+				{contentType: "text/plain", params: map[string]string{"charset": "utf-8"}, body: "no trailing line break"},
+				{contentType: "text/plain", params: map[string]string{}, body: ""},
+			}
+
+			userData, err := multipartUserData(parts)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(userData).To(HavePrefix(`Content-Type: multipart/mixed; boundary="==BOUNDARY-`))
+			Expect(parseMultipartUserData(userData)).To(Equal(parts))
+		})
+
+		It("should fail for invalid content types", func() {
+			Expect(multipartUserData([]mimePart{{contentType: "text/x shellscript", body: "foo"}})).Error().To(MatchError(`invalid content type "text/x shellscript" of MIME part`))
+		})
+	})
+
+	Describe("#mimeBoundary", func() {
+		It("should return the legacy boundary if no body contains it", func() {
+			Expect(mimeBoundary("foo", "bar ==BOUNDARY==\n", "-==BOUNDARY==\n")).To(Equal("==BOUNDARY=="))
+		})
+
+		It("should derive the boundary from the bodies if a body contains the legacy boundary", func() {
+			Expect(mimeBoundary("foo", "--==BOUNDARY==")).To(Equal(mimeBoundary("foo", "--==BOUNDARY==")))
+			Expect(mimeBoundary("foo", "--==BOUNDARY==")).NotTo(Equal(mimeBoundary("foo", "--==BOUNDARY==--")))
+			Expect(mimeBoundary("foo", "bar\n--==BOUNDARY==")).To(MatchRegexp(`^==BOUNDARY-[0-9a-f]{24}==$`))
+		})
+
+		It("should not return a boundary contained in a body", func() {
+			boundary := mimeBoundary("--==BOUNDARY==")
+
+			Expect(mimeBoundary("--==BOUNDARY==", "--"+boundary)).NotTo(Equal(boundary))
+		})
+	})
+})
+
+// parseMultipartUserData parses the given multipart user data back into its parts. The boundary is read from the
+// Content-Type header of the document. The headers of the document are not followed by an empty line, hence they are
+// not parsed with net/mail.
+func parseMultipartUserData(userData string) ([]mimePart, error) {
+	contentType, rest, _ := strings.Cut(userData, "\n")
+	mimeVersion, body, _ := strings.Cut(rest, "\n")
+
+	mediaType, params, err := mime.ParseMediaType(strings.TrimPrefix(contentType, "Content-Type: "))
+	if err != nil {
+		return nil, err
+	}
+	if mediaType != "multipart/mixed" || mimeVersion != "MIME-Version: 1.0" {
+		return nil, fmt.Errorf("user data is not a multipart/mixed document: %q, %q", mediaType, mimeVersion)
+	}
+
+	var (
+		parts  []mimePart
+		reader = multipart.NewReader(strings.NewReader(body), params["boundary"])
+	)
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			return parts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+
+		contentType, params, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, mimePart{contentType: contentType, params: params, body: string(body)})
+	}
+}
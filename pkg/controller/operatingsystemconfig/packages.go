@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"slices"
+	"time"
+
+	memoryonechostapi "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost"
+	susechostapi "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost"
+)
+
+// defaultPackages are the packages installed on every node, unless they are removed by the provider config.
+var defaultPackages = []string{"wget", "socat", "jq", "nfs-client"}
+
+// defaultPackageInstallTimeout is the time after which the provisioning gives up installing the packages, unless
+// configured otherwise by the provider config.
+const defaultPackageInstallTimeout = 10 * time.Minute
+
+// packageServices maps packages to the systemd services which must be enabled when the package is installed.
+var packageServices = map[string][]string{
+	"open-iscsi":      {"iscsid"},
+	"multipath-tools": {"multipathd"},
+}
+
+// packagesConfiguration returns the `packages` of the given provider config, at most one of which is set for the OS
+// type of the OperatingSystemConfig. The ones of memoryone-chost are converted, as both are configured the same way.
+func packagesConfiguration(suseCHostConfig *susechostapi.OperatingSystemConfiguration, memoryOneConfig *memoryonechostapi.OperatingSystemConfiguration) *susechostapi.Packages {
+	switch {
+	case suseCHostConfig != nil:
+		return suseCHostConfig.Packages
+	case memoryOneConfig != nil && memoryOneConfig.Packages != nil:
+		return &susechostapi.Packages{
+			Add:            memoryOneConfig.Packages.Add,
+			Remove:         memoryOneConfig.Packages.Remove,
+			InstallTimeout: memoryOneConfig.Packages.InstallTimeout,
+		}
+	}
+
+	return nil
+}
+
+// packagesToInstall returns the packages to install for the given packages config: the default packages without the
+// removed ones, followed by the added ones.
+func packagesToInstall(config *susechostapi.Packages) []string {
+	packages := slices.Clone(defaultPackages)
+	if config == nil {
+		return packages
+	}
+
+	packages = slices.DeleteFunc(packages, func(name string) bool {
+		return slices.Contains(config.Remove, name)
+	})
+
+	for _, name := range config.Add {
+		if !slices.Contains(packages, name) {
+			packages = append(packages, name)
+		}
+	}
+
+	return packages
+}
+
+// packageInstallTimeout returns the time after which the provisioning gives up installing the packages for the given
+// packages config.
+func packageInstallTimeout(config *susechostapi.Packages) time.Duration {
+	if config == nil || config.InstallTimeout == nil {
+		return defaultPackageInstallTimeout
+	}
+
+	return config.InstallTimeout.Duration
+}
+
+// packageServicesToEnable returns the systemd services which must be enabled for the given packages.
+func packageServicesToEnable(packages []string) []string {
+	var services []string
+
+	for _, name := range packages {
+		services = append(services, packageServices[name]...)
+	}
+
+	return services
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
+	memoryonechostapi "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost"
+	susechostapi "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost"
+)
+
+var _ = Describe("Packages", func() {
+	Describe("#packagesConfiguration", func() {
+		It("should return the packages of the suse-chost provider config", func() {
+			packages := &susechostapi.Packages{Add: []string{"open-iscsi"}}
+			Expect(packagesConfiguration(&susechostapi.OperatingSystemConfiguration{Packages: packages}, nil)).To(BeIdenticalTo(packages))
+		})
+
+		It("should convert the packages of the memoryone-chost provider config", func() {
+			Expect(packagesConfiguration(nil, &memoryonechostapi.OperatingSystemConfiguration{Packages: &memoryonechostapi.Packages{
+				Add:            []string{"open-iscsi"},
+				Remove:         []string{"wget"},
+				InstallTimeout: &metav1.Duration{Duration: time.Minute},
+			}})).To(Equal(&susechostapi.Packages{
+				Add:            []string{"open-iscsi"},
+				Remove:         []string{"wget"},
+				InstallTimeout: &metav1.Duration{Duration: time.Minute},
+			}))
+		})
+
+		It("should return nil if no packages are configured", func() {
+			Expect(packagesConfiguration(nil, nil)).To(BeNil())
+			Expect(packagesConfiguration(nil, &memoryonechostapi.OperatingSystemConfiguration{})).To(BeNil())
+		})
+	})
+
+	DescribeTable("#packagesToInstall",
+		func(config *susechostapi.Packages, expected []string) {
+			Expect(packagesToInstall(config)).To(Equal(expected))
+		},
+		Entry("no packages", nil, []string{"wget", "socat", "jq", "nfs-client"}),
+		Entry("added packages",
+			&susechostapi.Packages{Add: []string{"open-iscsi", "jq", "multipath-tools"}},
+			[]string{"wget", "socat", "jq", "nfs-client", "open-iscsi", "multipath-tools"},
+		),
+		Entry("removed packages",
+			&susechostapi.Packages{Remove: []string{"wget", "nfs-client", "foo"}},
+			[]string{"socat", "jq"},
+		),
+		Entry("all packages removed",
+			&susechostapi.Packages{Remove: []string{"wget", "socat", "jq", "nfs-client"}},
+			[]string{},
+		),
+	)
+
+	It("should not modify the default packages", func() {
+		packagesToInstall(&susechostapi.Packages{Remove: []string{"wget"}, Add: []string{"foo"}})
+		Expect(defaultPackages).To(Equal([]string{"wget", "socat", "jq", "nfs-client"}))
+	})
+
+	Describe("#packageInstallTimeout", func() {
+		It("should return the default timeout", func() {
+			Expect(packageInstallTimeout(nil)).To(Equal(10 * time.Minute))
+			Expect(packageInstallTimeout(&susechostapi.Packages{})).To(Equal(10 * time.Minute))
+		})
+
+		It("should return the configured timeout", func() {
+			Expect(packageInstallTimeout(&susechostapi.Packages{InstallTimeout: &metav1.Duration{Duration: time.Minute}})).To(Equal(time.Minute))
+		})
+	})
+
+	Describe("packages fragment", func() {
+		installPackagesScript := func(packages []string, timeout time.Duration) string {
+			GinkgoHelper()
+			script, err := renderProvisionScriptFragment(config.ProvisionScriptFragmentPackages, &provisionScriptData{
+				Packages:                     packages,
+				PackageInstallTimeoutSeconds: int(timeout.Seconds()),
+				PackageServices:              packageServicesToEnable(packages),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return script
+		}
+
+		It("should install the packages with a bounded retry", func() {
+			Expect(installPackagesScript([]string{"wget", "jq"}, 90*time.Second)).To(Equal(`PACKAGES=('wget' 'jq')
+PACKAGES_INSTALL_DEADLINE=$((SECONDS + 90))
+PACKAGES_INSTALL_BACKOFF=1
+mkdir -p "$(dirname "/var/lib/osc/package-installation-status")"
+packages_install_failed() {
+  echo "$1" >&2
+  echo "$1" > /dev/console 2>/dev/null || true
+  echo "$1" > "/var/lib/osc/package-installation-status"
+  exit 1
+}
+while true; do
+  PACKAGES_INSTALL_EXIT_CODE=0
+  zypper -q install -y "${PACKAGES[@]}" || PACKAGES_INSTALL_EXIT_CODE=$?
+  case "${PACKAGES_INSTALL_EXIT_CODE}" in
+    0)
+      break
+      ;;
+    6|7|8|105)
+      ;;
+    100|101|102|103|106)
+      echo "zypper exited with informational code ${PACKAGES_INSTALL_EXIT_CODE}"
+      break
+      ;;
+    *)
+      packages_install_failed "failed to install packages ${PACKAGES[*]}: zypper exited with code ${PACKAGES_INSTALL_EXIT_CODE}"
+      ;;
+  esac
+  if (( SECONDS + PACKAGES_INSTALL_BACKOFF > PACKAGES_INSTALL_DEADLINE )); then
+    packages_install_failed "failed to install packages ${PACKAGES[*]}: zypper exited with code ${PACKAGES_INSTALL_EXIT_CODE}, giving up after 90 seconds"
+  fi
+  echo "zypper exited with code ${PACKAGES_INSTALL_EXIT_CODE}, retrying in ${PACKAGES_INSTALL_BACKOFF} seconds"
+  sleep "${PACKAGES_INSTALL_BACKOFF}"
+  PACKAGES_INSTALL_BACKOFF=$(( PACKAGES_INSTALL_BACKOFF * 2 > 30 ? 30 : PACKAGES_INSTALL_BACKOFF * 2 ))
+done
+echo "installed packages ${PACKAGES[*]}" > "/var/lib/osc/package-installation-status"
+`))
+		})
+
+		It("should enable the services of the packages", func() {
+			Expect(installPackagesScript([]string{"wget", "multipath-tools", "open-iscsi"}, time.Minute)).To(HaveSuffix(`echo "installed packages ${PACKAGES[*]}" > "/var/lib/osc/package-installation-status"
+systemctl enable --now 'multipathd'
+systemctl enable --now 'iscsid'
+`))
+		})
+	})
+})
@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"embed"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/gardener/gardener/extensions/pkg/controller/operatingsystemconfig"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
+)
+
+// The provision script consists of the fragments below, in this order. Only the fragments in the config package may
+// be disabled in the controller configuration, all others are required for joining the node to the cluster.
+const (
+	provisionScriptFragmentFiles        = "files"
+	provisionScriptFragmentNode         = "node"
+	provisionScriptFragmentCgroup       = "cgroup"
+	provisionScriptFragmentUnits        = "units"
+	provisionScriptFragmentCgroupReboot = "cgroup-reboot"
+)
+
+// provisionScriptFragment is a part of the provision script rendered from the embedded template
+// `templates/<name>.sh.tpl`.
+type provisionScriptFragment struct {
+	name string
+	// included returns whether the fragment is part of the provision script. Fragments without it are always
+	// included, unless they are disabled.
+	included func(*provisionScriptData) bool
+	// afterApplied fragments run after the provisioning has been marked as applied.
+	afterApplied bool
+}
+
+var provisionScriptFragments = []provisionScriptFragment{
+	{name: provisionScriptFragmentFiles},
+	{name: config.ProvisionScriptFragmentRepositories, included: func(data *provisionScriptData) bool { return len(data.repositories) > 0 }},
+	{name: config.ProvisionScriptFragmentPackages, included: func(data *provisionScriptData) bool {
+		return len(data.Packages) > 0 && len(data.AwaitedRepositoryFilePaths) == 0
+	}},
+	{name: provisionScriptFragmentNode},
+	{name: config.ProvisionScriptFragmentDocker},
+	{name: config.ProvisionScriptFragmentContainerd},
+	{name: config.ProvisionScriptFragmentJournald},
+	{name: provisionScriptFragmentCgroup, included: func(data *provisionScriptData) bool { return data.UnifiedCgroupHierarchy }},
+	{name: provisionScriptFragmentUnits, included: func(data *provisionScriptData) bool { return len(data.Units) > 0 }},
+	// The repositories with credentials are written by gardener-node-agent, which is started by the units, hence the
+	// packages are installed after the units if they may come from these repositories.
+	{name: config.ProvisionScriptFragmentPackages, included: func(data *provisionScriptData) bool {
+		return len(data.Packages) > 0 && len(data.AwaitedRepositoryFilePaths) > 0
+	}},
+	// The reboot must happen after the provisioning has been marked as applied, so that the script does not run again
+	// after the reboot.
+	{name: provisionScriptFragmentCgroupReboot, included: func(data *provisionScriptData) bool { return data.UnifiedCgroupHierarchy }, afterApplied: true},
+}
+
+// provisionFiles are the files and units a provision script fragment writes to disk.
+type provisionFiles struct {
+	files []extensionsv1alpha1.File
+	units []extensionsv1alpha1.Unit
+}
+
+// provisionScriptData is the data the provision script fragments are rendered with. The `*Script` fields contain
+// scripts writing the files and units of the fragments to disk, they are empty if cloud-init writes them.
+type provisionScriptData struct {
+	FilesScript string
+	UnitsScript string
+
+	RepositoryFilesScript string
+	GPGKeyPaths           []string
+
+	AwaitedRepositoryFilePaths []string
+	AwaitedGPGKeyPaths         []string
+
+	Packages                     []string
+	PackageInstallTimeoutSeconds int
+	PackageServices              []string
+
+	DockerFixFilesScript string
+	DockerFixUnitsScript string
+	DockerFixUnitName    string
+
+	ContainerdConfigFilesScript string
+	ContainerdUnitsScript       string
+	ContainerdConfigPath        string
+	ContainerdConfigV2Path      string
+	ContainerdConfigV3Path      string
+
+	JournaldFilesScript string
+	JournaldUnitName    string
+
+	UnifiedCgroupHierarchy bool
+
+	Units []extensionsv1alpha1.Unit
+
+	// repositories are the zypper repositories configured by the repositories fragment.
+	repositories []zypperRepository
+	// files are the files and units written to disk by the fragment with the given name.
+	files map[string]provisionFiles
+}
+
+var (
+	//go:embed templates/*.sh.tpl
+	templatesFS embed.FS
+
+	provisionScriptTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+		"shellQuote":    shellQuote,
+		"shellQuoteAll": shellQuoteAll,
+		"unitAction":    unitAction,
+	}).ParseFS(templatesFS, "templates/*.sh.tpl"))
+)
+
+// renderProvisionScript renders the provision script from all fragments which are included for the given data and not
+// disabled. It returns the script and the names of the fragments it consists of.
+func renderProvisionScript(data *provisionScriptData, disabledFragments []string) (string, []string, error) {
+	var (
+		scripts, afterApplied []string
+		fragments             []string
+	)
+
+	for _, fragment := range includedProvisionScriptFragments(data, disabledFragments) {
+		out, err := renderProvisionScriptFragment(fragment.name, data)
+		if err != nil {
+			return "", nil, err
+		}
+		if out == "" {
+			continue
+		}
+
+		if fragment.afterApplied {
+			afterApplied = append(afterApplied, out)
+		} else {
+			scripts = append(scripts, out)
+		}
+		fragments = append(fragments, fragment.name)
+	}
+
+	// The provisioning script must run only once.
+	script := operatingsystemconfig.WrapProvisionOSCIntoOneshotScript("#!/bin/bash\n" + strings.Join(scripts, "\n"))
+	for _, out := range afterApplied {
+		script += "\n" + out
+	}
+
+	return script, fragments, nil
+}
+
+// includedProvisionScriptFragments returns the fragments which are included for the given data and not disabled, in the
+// order they are run.
+func includedProvisionScriptFragments(data *provisionScriptData, disabledFragments []string) []provisionScriptFragment {
+	var fragments []provisionScriptFragment
+	for _, fragment := range provisionScriptFragments {
+		if slices.Contains(disabledFragments, fragment.name) || (fragment.included != nil && !fragment.included(data)) {
+			continue
+		}
+		fragments = append(fragments, fragment)
+	}
+	return fragments
+}
+
+// renderProvisionScriptFragment renders the fragment with the given name. The result is empty or ends with a line
+// break.
+func renderProvisionScriptFragment(name string, data *provisionScriptData) (string, error) {
+	var out strings.Builder
+	if err := provisionScriptTemplates.ExecuteTemplate(&out, name+".sh.tpl", data); err != nil {
+		return "", fmt.Errorf("failed rendering provision script fragment %q: %w", name, err)
+	}
+
+	script := strings.TrimSpace(out.String())
+	if script == "" {
+		return "", nil
+	}
+	return script + "\n", nil
+}
+
+// unitAction returns how the given unit is applied by the provision script, see `templates/units.sh.tpl`.
+func unitAction(unit extensionsv1alpha1.Unit) string {
+	switch {
+	case !ptr.Deref(unit.Enable, true):
+		return "disable"
+	case ptr.Deref(unit.Command, "") == extensionsv1alpha1.CommandStop:
+		return "stop"
+	case unit.Content == nil:
+		return "try-restart"
+	default:
+		return "restart"
+	}
+}
@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"context"
+	"io/fs"
+	"slices"
+	"strings"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
+	"github.com/gardener/gardener-extension-os-suse-chost/test/script"
+)
+
+var _ = Describe("Provision", func() {
+	var data *provisionScriptData
+
+	BeforeEach(func() {
+		data = &provisionScriptData{
+			FilesScript:                  "\nmkdir -p \"/some\"\n\ncat << EOF | base64 -d > \"/some/file\"\nYmFy\nEOF",
+			UnitsScript:                  "\ncat << EOF | base64 -d > \"/etc/systemd/system/some-unit\"\nZm9v\nEOF",
+			RepositoryFilesScript:        "\nmkdir -p \"/etc/zypp/repos.d\"\n\ncat << EOF | base64 -d > \"/etc/zypp/repos.d/rmt.repo\"\nZm9v\nEOF",
+			GPGKeyPaths:                  []string{"/etc/zypp/keys/rmt.asc"},
+			Packages:                     []string{"wget", "open-iscsi"},
+			PackageInstallTimeoutSeconds: 60,
+			PackageServices:              []string{"iscsid"},
+			DockerFixUnitsScript:         "\ncat << EOF | base64 -d > \"/etc/systemd/system/containerd-docker-fix.service\"\nZm9v\nEOF",
+			DockerFixUnitName:            containerdDockerFixUnitName,
+			ContainerdConfigFilesScript:  "\nmkdir -p \"/var/lib/osc/containerd\"\n\necho foo > \"" + containerdConfigVersionPath(2) + "\"\necho foo > \"" + containerdConfigVersionPath(3) + "\"",
+			ContainerdConfigPath:         containerdConfigPath,
+			ContainerdConfigV2Path:       containerdConfigVersionPath(2),
+			ContainerdConfigV3Path:       containerdConfigVersionPath(3),
+			JournaldUnitName:             journaldUnitName,
+			UnifiedCgroupHierarchy:       true,
+			Units:                        []extensionsv1alpha1.Unit{{Name: "some-unit", Content: ptr.To("foo")}},
+			repositories:                 []zypperRepository{{name: "rmt", url: "https://rmt.example.com"}},
+			files: map[string]provisionFiles{
+				config.ProvisionScriptFragmentRepositories: {files: []extensionsv1alpha1.File{{Path: "/etc/zypp/repos.d/rmt.repo", Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: "foo"}}}}},
+			},
+		}
+	})
+
+	It("should have a fragment for every template", func() {
+		templates, err := fs.Glob(templatesFS, "templates/*.sh.tpl")
+		Expect(err).NotTo(HaveOccurred())
+
+		var names []string
+		for _, fragment := range provisionScriptFragments {
+			if name := "templates/" + fragment.name + ".sh.tpl"; !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+		Expect(names).To(ConsistOf(templates))
+	})
+
+	DescribeTable("should render fragments which can run on their own",
+		func(name string, expectedCommands []string) {
+			fragment, err := renderProvisionScriptFragment(name, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fragment).To(HaveSuffix("\n"))
+			Expect(script.Check("#!/bin/bash\n" + fragment)).To(Succeed())
+
+			sandbox, err := script.NewSandbox(GinkgoT().TempDir(), nil)
+			Expect(err).NotTo(HaveOccurred())
+			output, err := sandbox.Run(context.Background(), "#!/bin/bash\nset -o errexit\n"+fragment)
+			Expect(err).NotTo(HaveOccurred(), string(output))
+			Expect(sandbox.Commands()).To(Equal(expectedCommands))
+		},
+		Entry("files", provisionScriptFragmentFiles, []string{"systemctl daemon-reload"}),
+		Entry("repositories", config.ProvisionScriptFragmentRepositories, []string{"rpm --import /etc/zypp/keys/rmt.asc"}),
+		Entry("packages", config.ProvisionScriptFragmentPackages, []string{"zypper -q install -y wget open-iscsi", "systemctl enable --now iscsid"}),
+		Entry("node", provisionScriptFragmentNode, []string{"ln -s /bin/ip /usr/bin/ip", "hostname"}),
+		Entry("docker", config.ProvisionScriptFragmentDocker, []string{"systemctl daemon-reload", "systemctl enable containerd-docker-fix.service", "systemctl restart containerd-docker-fix.service"}),
+		Entry("containerd", config.ProvisionScriptFragmentContainerd, []string{"containerd --version", "systemctl daemon-reload", "ln -s /usr/sbin/containerd-ctr /usr/sbin/ctr", "systemctl enable containerd", "systemctl restart containerd"}),
+		Entry("journald", config.ProvisionScriptFragmentJournald, []string{"systemctl restart systemd-journald.service"}),
+		Entry("units", provisionScriptFragmentUnits, []string{"systemctl enable some-unit", "systemctl restart --no-block some-unit"}),
+		Entry("cgroup-reboot", provisionScriptFragmentCgroupReboot, []string{"systemctl reboot"}),
+	)
+
+	Describe("#renderProvisionScript", func() {
+		It("should render all included fragments in order", func() {
+			userData, fragments, err := renderProvisionScript(data, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fragments).To(Equal([]string{"files", "repositories", "packages", "node", "docker", "containerd", "journald", "cgroup", "units", "cgroup-reboot"}))
+			Expect(script.Check(userData)).To(Succeed())
+
+			// The reboot runs after the provisioning has been marked as applied.
+			Expect(strings.Index(userData, "touch /var/lib/osc/provision-osc-applied")).To(BeNumerically("<", strings.Index(userData, "systemctl reboot")))
+		})
+
+		It("should install the packages after the units if repositories with credentials are awaited", func() {
+			data.AwaitedRepositoryFilePaths = []string{"/etc/zypp/credentials.d/mirror", "/etc/zypp/repos.d/mirror.repo"}
+
+			userData, fragments, err := renderProvisionScript(data, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fragments).To(Equal([]string{"files", "repositories", "node", "docker", "containerd", "journald", "cgroup", "units", "packages", "cgroup-reboot"}))
+			Expect(script.Check(userData)).To(Succeed())
+
+			// The packages are installed before the provisioning has been marked as applied.
+			Expect(strings.Index(userData, "zypper -q install")).To(BeNumerically("<", strings.Index(userData, "touch /var/lib/osc/provision-osc-applied")))
+		})
+
+		It("should leave out fragments which are not included for the data", func() {
+			data.repositories = nil
+			data.files = nil
+			data.Packages = nil
+			data.UnifiedCgroupHierarchy = false
+			data.Units = nil
+
+			userData, fragments, err := renderProvisionScript(data, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fragments).To(Equal([]string{"files", "node", "docker", "containerd", "journald"}))
+			Expect(userData).NotTo(ContainSubstring("zypper"))
+			Expect(userData).NotTo(ContainSubstring("systemctl reboot"))
+		})
+
+		It("should leave out disabled fragments", func() {
+			userData, fragments, err := renderProvisionScript(data, []string{"docker", "packages", "journald"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fragments).To(Equal([]string{"files", "repositories", "node", "containerd", "cgroup", "units", "cgroup-reboot"}))
+			Expect(userData).NotTo(ContainSubstring("zypper"))
+			Expect(userData).NotTo(ContainSubstring(containerdDockerFixUnitName))
+			Expect(userData).NotTo(ContainSubstring(journaldUnitName))
+		})
+	})
+})
@@ -0,0 +1,271 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/extensions"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	susechostapi "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost"
+)
+
+const (
+	zyppReposDir       = "/etc/zypp/repos.d"
+	zyppCredentialsDir = "/etc/zypp/credentials.d"
+	zyppKeysDir        = "/etc/zypp/keys"
+
+	// gpgKeysImportUnitName is the unit importing the GPG keys of the repositories configured on reconciliation.
+	gpgKeysImportUnitName = "zypper-gpg-keys-import.service"
+)
+
+// zypperRepository is a zypper repository configured on the nodes, either by the operator of the extension or by the
+// provider config of the worker pool.
+type zypperRepository struct {
+	name     string
+	url      string
+	gpgKey   *string
+	priority *int32
+	// credentialsSecretRef references the Secret with the credentials of a repository of the operator.
+	credentialsSecretRef *corev1.SecretReference
+	// credentialsResourceName is the name of the resource of the Shoot referencing the Secret with the credentials of a
+	// repository of the provider config.
+	credentialsResourceName *string
+	// credentials are the contents of the Secret with the `username` and `password` for the repository, if any, see
+	// resolveRepositoryCredentials.
+	credentials *corev1.Secret
+}
+
+// hasCredentials returns whether the repository requires credentials. The user data can be read from the instance
+// metadata of the nodes, hence repositories with credentials are never part of the provision script. They are returned
+// as extension files on reconciliation instead, so that gardener-node-agent writes them to the nodes.
+func (r zypperRepository) hasCredentials() bool {
+	return r.credentialsSecretRef != nil || r.credentialsResourceName != nil
+}
+
+// repositories returns the zypper repositories of the given provider config: the repositories configured by the
+// operator first, followed by the ones of the provider config.
+func (a *actuator) repositories(config *susechostapi.OperatingSystemConfiguration) ([]zypperRepository, error) {
+	var repositories []zypperRepository
+
+	for _, repository := range a.config.Repositories {
+		repositories = append(repositories, zypperRepository{
+			name:                 repository.Name,
+			url:                  repository.URL,
+			gpgKey:               repository.GPGKey,
+			priority:             repository.Priority,
+			credentialsSecretRef: repository.CredentialsSecretRef,
+		})
+	}
+
+	if config == nil {
+		return repositories, nil
+	}
+
+	for _, repository := range config.Repositories {
+		for _, r := range a.config.Repositories {
+			if r.Name == repository.Name {
+				return nil, fmt.Errorf("repository %q is already configured by the operator of the extension", repository.Name)
+			}
+		}
+
+		repositories = append(repositories, zypperRepository{
+			name:                    repository.Name,
+			url:                     repository.URL,
+			gpgKey:                  repository.GPGKey,
+			priority:                repository.Priority,
+			credentialsResourceName: repository.CredentialsResourceName,
+		})
+	}
+
+	return repositories, nil
+}
+
+// repositoriesWithCredentials returns the given repositories which require credentials if withCredentials is true,
+// otherwise the ones which do not.
+func repositoriesWithCredentials(repositories []zypperRepository, withCredentials bool) []zypperRepository {
+	var filtered []zypperRepository
+
+	for _, repository := range repositories {
+		if repository.hasCredentials() == withCredentials {
+			filtered = append(filtered, repository)
+		}
+	}
+
+	return filtered
+}
+
+// resolveRepositoryCredentials reads the credentials of the given repositories: the ones of the operator from the
+// referenced Secrets in the seed, the ones of the provider config from the Secrets referenced by the Shoot's resources.
+func (a *actuator) resolveRepositoryCredentials(ctx context.Context, osc *extensionsv1alpha1.OperatingSystemConfig, cluster *extensions.Cluster, repositories []zypperRepository) error {
+	for i, repository := range repositories {
+		secret := &corev1.Secret{}
+
+		switch {
+		case repository.credentialsSecretRef != nil:
+			ref := repository.credentialsSecretRef
+			if err := a.client.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+				return fmt.Errorf("failed to get credentials secret %s/%s for repository %q: %w", ref.Namespace, ref.Name, repository.name, err)
+			}
+
+		case repository.credentialsResourceName != nil:
+			resourceName := repository.credentialsResourceName
+			if cluster == nil || cluster.Shoot == nil {
+				return fmt.Errorf("cannot resolve credentials resource %q for repository %q without a Shoot", *resourceName, repository.name)
+			}
+
+			resource := v1beta1helper.GetResourceByName(cluster.Shoot.Spec.Resources, *resourceName)
+			if resource == nil {
+				return fmt.Errorf("credentials resource %q for repository %q not found in the Shoot's resources", *resourceName, repository.name)
+			}
+
+			if err := extensionscontroller.GetObjectByReference(ctx, a.client, &resource.ResourceRef, osc.Namespace, secret); err != nil {
+				return fmt.Errorf("failed to get credentials secret for repository %q: %w", repository.name, err)
+			}
+
+		default:
+			continue
+		}
+
+		repositories[i].credentials = secret
+	}
+
+	return nil
+}
+
+// gpgKeysImportUnits returns the unit importing the GPG keys of the given repositories into the RPM database, if any.
+// The unit is restarted by gardener-node-agent whenever one of the keys changes.
+func gpgKeysImportUnits(repositories []zypperRepository) []extensionsv1alpha1.Unit {
+	paths := gpgKeyPaths(repositories)
+	if len(paths) == 0 {
+		return nil
+	}
+
+	return []extensionsv1alpha1.Unit{{
+		Name:   gpgKeysImportUnitName,
+		Enable: ptr.To(true),
+		Content: ptr.To(`[Unit]
+Description=Import the GPG keys of the zypper repositories with credentials
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/usr/bin/rpm --import ` + strings.Join(paths, " ") + `
+
+[Install]
+WantedBy=multi-user.target
+`),
+		FilePaths: paths,
+	}}
+}
+
+// repositoryFiles returns the files configuring the given repositories on the nodes: the repository definitions,
+// the credentials and the GPG keys.
+func repositoryFiles(repositories []zypperRepository) ([]extensionsv1alpha1.File, error) {
+	var files []extensionsv1alpha1.File
+
+	for _, repository := range repositories {
+		repoURL := repository.url
+
+		if repository.credentials != nil {
+			username, password := repository.credentials.Data["username"], repository.credentials.Data["password"]
+			if len(username) == 0 || len(password) == 0 {
+				return nil, fmt.Errorf("credentials secret %s/%s for repository %q must contain a 'username' and a 'password'", repository.credentials.Namespace, repository.credentials.Name, repository.name)
+			}
+			if strings.ContainsAny(string(username)+string(password), "\r\n") {
+				return nil, fmt.Errorf("credentials secret %s/%s for repository %q must not contain line breaks", repository.credentials.Namespace, repository.credentials.Name, repository.name)
+			}
+
+			u, err := url.Parse(repoURL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse URL of repository %q: %w", repository.name, err)
+			}
+			query := u.Query()
+			query.Set("credentials", repository.name)
+			u.RawQuery = query.Encode()
+			repoURL = u.String()
+
+			files = append(files, inlineFile(path.Join(zyppCredentialsDir, repository.name), 0600, fmt.Sprintf("username=%s\npassword=%s\n", username, password)))
+		}
+
+		repo := fmt.Sprintf(`[%[1]s]
+name=%[1]s
+enabled=1
+autorefresh=1
+baseurl=%[2]s
+type=rpm-md
+gpgcheck=1
+`, repository.name, repoURL)
+
+		if repository.gpgKey != nil {
+			keyPath := gpgKeyPath(repository)
+			repo += fmt.Sprintf("gpgkey=file://%s\n", keyPath)
+			files = append(files, inlineFile(keyPath, 0644, *repository.gpgKey))
+		}
+		if repository.priority != nil {
+			repo += fmt.Sprintf("priority=%d\n", *repository.priority)
+		}
+
+		files = append(files, inlineFile(path.Join(zyppReposDir, repository.name+".repo"), 0644, repo))
+	}
+
+	return files, nil
+}
+
+// repositoryFilePaths returns the paths of the files configuring the given repositories, see repositoryFiles.
+func repositoryFilePaths(repositories []zypperRepository) []string {
+	var paths []string
+
+	for _, repository := range repositories {
+		if repository.hasCredentials() {
+			paths = append(paths, path.Join(zyppCredentialsDir, repository.name))
+		}
+		if repository.gpgKey != nil {
+			paths = append(paths, gpgKeyPath(repository))
+		}
+		paths = append(paths, path.Join(zyppReposDir, repository.name+".repo"))
+	}
+
+	return paths
+}
+
+// gpgKeyPaths returns the paths of the GPG keys of the given repositories.
+func gpgKeyPaths(repositories []zypperRepository) []string {
+	var paths []string
+
+	for _, repository := range repositories {
+		if repository.gpgKey != nil {
+			paths = append(paths, gpgKeyPath(repository))
+		}
+	}
+
+	return paths
+}
+
+func gpgKeyPath(repository zypperRepository) string {
+	return path.Join(zyppKeysDir, repository.name+".asc")
+}
+
+func inlineFile(filePath string, permissions uint32, data string) extensionsv1alpha1.File {
+	return extensionsv1alpha1.File{
+		Path:        filePath,
+		Permissions: ptr.To(permissions),
+		Content: extensionsv1alpha1.FileContent{
+			Inline: &extensionsv1alpha1.FileContentInline{
+				Data: data,
+			},
+		},
+	}
+}
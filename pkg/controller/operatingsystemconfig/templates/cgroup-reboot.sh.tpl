@@ -0,0 +1,8 @@
+{{- /* Reboots the node once if it is not yet running with the unified cgroup hierarchy. The marker is written right
+before the node reboots, it ensures the node reboots at most once, even if the kernel parameter does not take effect. */ -}}
+if [[ ! -f /sys/fs/cgroup/cgroup.controllers && ! -f "/var/lib/osc/unified-cgroup-hierarchy-reboot" ]]; then
+  echo "Rebooting to activate the unified cgroup hierarchy (cgroup v2)..."
+  mkdir -p /var/lib/osc
+  touch "/var/lib/osc/unified-cgroup-hierarchy-reboot"
+  systemctl reboot
+fi
@@ -0,0 +1,18 @@
+{{- /* Adds the kernel parameter for booting with the unified cgroup hierarchy (cgroup v2) to the boot loader
+configuration. `/sys/fs/cgroup/cgroup.controllers` only exists if the unified hierarchy is mounted at `/sys/fs/cgroup`.
+The script fails if the parameter cannot be added, so that the node is not rebooted in vain. */ -}}
+# Boot with the unified cgroup hierarchy (cgroup v2)
+if [[ ! -f /sys/fs/cgroup/cgroup.controllers ]]; then
+  if ! grep -qs 'systemd.unified_cgroup_hierarchy=1' /etc/default/grub; then
+    if grep -qs '^GRUB_CMDLINE_LINUX_DEFAULT=' /etc/default/grub; then
+      sed -re 's/^GRUB_CMDLINE_LINUX_DEFAULT="(.*)"$/GRUB_CMDLINE_LINUX_DEFAULT="\1 systemd.unified_cgroup_hierarchy=1"/' -i /etc/default/grub
+    else
+      echo 'GRUB_CMDLINE_LINUX_DEFAULT="systemd.unified_cgroup_hierarchy=1"' >> /etc/default/grub
+    fi
+    if ! grep -q 'systemd.unified_cgroup_hierarchy=1' /etc/default/grub; then
+      echo "Failed to add systemd.unified_cgroup_hierarchy=1 to GRUB_CMDLINE_LINUX_DEFAULT in /etc/default/grub" >&2
+      exit 1
+    fi
+    grub2-mkconfig -o /boot/grub2/grub.cfg
+  fi
+fi
@@ -0,0 +1,20 @@
+{{- /* Installs the containerd configuration matching the major version of the containerd binary of the image, unless
+the image already ships a configuration. The configuration is rendered for all supported config versions. */ -}}
+{{ .ContainerdConfigFilesScript }}
+CONTAINERD_CONFIG_PATH={{ shellQuote .ContainerdConfigPath }}
+if [[ ! -s "${CONTAINERD_CONFIG_PATH}" || $(cat "${CONTAINERD_CONFIG_PATH}") == "# See containerd-config.toml(5) for documentation." ]]; then
+  CONTAINERD_VERSION=$(containerd --version | awk '{print $3}')
+  CONTAINERD_VERSION="${CONTAINERD_VERSION#v}"
+  if [[ "${CONTAINERD_VERSION%%.*}" == "1" ]]; then
+    CONTAINERD_CONFIG_VERSION_PATH={{ shellQuote .ContainerdConfigV2Path }}
+  else
+    CONTAINERD_CONFIG_VERSION_PATH={{ shellQuote .ContainerdConfigV3Path }}
+  fi
+  mkdir -p "$(dirname "${CONTAINERD_CONFIG_PATH}")"
+  cp "${CONTAINERD_CONFIG_VERSION_PATH}" "${CONTAINERD_CONFIG_PATH}"
+  chmod 0644 "${CONTAINERD_CONFIG_PATH}"
+fi
+{{ .ContainerdUnitsScript }}
+systemctl daemon-reload
+ln -s /usr/sbin/containerd-ctr /usr/sbin/ctr
+systemctl enable containerd && systemctl restart containerd
@@ -0,0 +1,4 @@
+{{- /* Removes the conflict of containerd with docker and disables docker, see docs/systemd-units.md. */ -}}
+{{ .DockerFixFilesScript }}{{ .DockerFixUnitsScript }}
+systemctl daemon-reload
+systemctl enable {{ shellQuote .DockerFixUnitName }} && systemctl restart {{ shellQuote .DockerFixUnitName }}
@@ -0,0 +1,3 @@
+{{- /* Writes the files and units of the OperatingSystemConfig. */ -}}
+{{ .FilesScript }}{{ .UnitsScript }}
+systemctl daemon-reload
@@ -0,0 +1,3 @@
+{{- /* Makes journald write the logs to /var/log instead of /run/log. */ -}}
+{{ .JournaldFilesScript }}
+systemctl restart {{ shellQuote .JournaldUnitName }}
@@ -0,0 +1,3 @@
+{{- /* Prepares the node for joining the cluster. */ -}}
+ln -s /bin/ip /usr/bin/ip
+if [ ! -s /etc/hostname ]; then hostname > /etc/hostname; fi
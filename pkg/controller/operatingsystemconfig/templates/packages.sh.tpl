@@ -0,0 +1,60 @@
+{{- /* Installs the packages and enables the services they require.
+zypper is only retried for exit codes indicating a transient problem, i.e., while zypp is locked by another process (7),
+no repositories are defined yet (6), packages could not be downloaded or committed (8), or zypper was interrupted
+(105). The retries use an exponential backoff (capped at 30s) until the timeout is exceeded. The informational exit codes
+100-103 (updates, a reboot or a restart are needed) and 106 (some repositories have been skipped) are treated as success.
+All other exit codes fail the installation right away, in particular 104 (a package was not found) and 107 (an RPM
+scriptlet failed). The files of repositories with credentials are written by gardener-node-agent, hence the script waits
+for them until the timeout is exceeded and imports their GPG keys before it installs the packages. Failures are reported
+to the console and to the well-known status file, and the script exits with an error. */ -}}
+PACKAGES=({{ shellQuoteAll .Packages }})
+PACKAGES_INSTALL_DEADLINE=$((SECONDS + {{ .PackageInstallTimeoutSeconds }}))
+PACKAGES_INSTALL_BACKOFF=1
+mkdir -p "$(dirname "/var/lib/osc/package-installation-status")"
+packages_install_failed() {
+  echo "$1" >&2
+  echo "$1" > /dev/console 2>/dev/null || true
+  echo "$1" > "/var/lib/osc/package-installation-status"
+  exit 1
+}
+{{- if .AwaitedRepositoryFilePaths }}
+for file in {{ shellQuoteAll .AwaitedRepositoryFilePaths }}; do
+  while [ ! -f "${file}" ]; do
+    if (( SECONDS + 1 > PACKAGES_INSTALL_DEADLINE )); then
+      packages_install_failed "failed to install packages ${PACKAGES[*]}: ${file} has not been written by gardener-node-agent within {{ .PackageInstallTimeoutSeconds }} seconds"
+    fi
+    sleep 1
+  done
+done
+{{- range .AwaitedGPGKeyPaths }}
+rpm --import {{ shellQuote . }}
+{{- end }}
+{{- end }}
+while true; do
+  PACKAGES_INSTALL_EXIT_CODE=0
+  zypper -q install -y "${PACKAGES[@]}" || PACKAGES_INSTALL_EXIT_CODE=$?
+  case "${PACKAGES_INSTALL_EXIT_CODE}" in
+    0)
+      break
+      ;;
+    6|7|8|105)
+      ;;
+    100|101|102|103|106)
+      echo "zypper exited with informational code ${PACKAGES_INSTALL_EXIT_CODE}"
+      break
+      ;;
+    *)
+      packages_install_failed "failed to install packages ${PACKAGES[*]}: zypper exited with code ${PACKAGES_INSTALL_EXIT_CODE}"
+      ;;
+  esac
+  if (( SECONDS + PACKAGES_INSTALL_BACKOFF > PACKAGES_INSTALL_DEADLINE )); then
+    packages_install_failed "failed to install packages ${PACKAGES[*]}: zypper exited with code ${PACKAGES_INSTALL_EXIT_CODE}, giving up after {{ .PackageInstallTimeoutSeconds }} seconds"
+  fi
+  echo "zypper exited with code ${PACKAGES_INSTALL_EXIT_CODE}, retrying in ${PACKAGES_INSTALL_BACKOFF} seconds"
+  sleep "${PACKAGES_INSTALL_BACKOFF}"
+  PACKAGES_INSTALL_BACKOFF=$(( PACKAGES_INSTALL_BACKOFF * 2 > 30 ? 30 : PACKAGES_INSTALL_BACKOFF * 2 ))
+done
+echo "installed packages ${PACKAGES[*]}" > "/var/lib/osc/package-installation-status"
+{{- range .PackageServices }}
+systemctl enable --now {{ shellQuote . }}
+{{- end }}
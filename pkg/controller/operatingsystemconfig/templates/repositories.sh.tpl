@@ -0,0 +1,6 @@
+{{- /* Configures the zypper repositories and imports their GPG keys into the RPM database, so that the packages of
+the repositories are trusted. */ -}}
+{{ .RepositoryFilesScript }}
+{{- range .GPGKeyPaths }}
+rpm --import {{ shellQuote . }}
+{{- end }}
@@ -0,0 +1,25 @@
+{{- /* Applies the units of the OperatingSystemConfig like gardener-node-agent does when it reconciles them for the first
+time, see applyChangedUnits in pkg/nodeagent/controller/operatingsystemconfig/reconciler.go and getCommandToExecute in
+pkg/nodeagent/controller/operatingsystemconfig/changes.go of gardener:
+  - units are enabled unless Enable is false, in which case they are disabled, also units without content which only
+    carry drop-ins for a unit shipped with the image,
+  - units are stopped if they are disabled (regardless of Command) or their Command is stop, otherwise they are
+    restarted. Units without content are only restarted if they are running, so that the drop-ins do not start a unit
+    of the image which is not meant to run yet.
+The jobs are not awaited, hence systemd runs them according to the dependencies of the units and not in the declared
+order, like gardener-node-agent which executes the unit commands in parallel. */ -}}
+{{- range .Units }}
+{{- $name := shellQuote .Name }}
+{{- $action := unitAction . }}
+{{- if eq $action "disable" }}
+systemctl disable {{ $name }}
+systemctl stop --no-block {{ $name }}
+{{- else if eq $action "stop" }}
+systemctl enable {{ $name }}
+systemctl stop --no-block {{ $name }}
+{{- else if eq $action "try-restart" }}
+systemctl enable {{ $name }} && systemctl try-restart --no-block {{ $name }}
+{{- else }}
+systemctl enable {{ $name }} && systemctl restart --no-block {{ $name }}
+{{- end }}
+{{- end }}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"k8s.io/utils/ptr"
+)
+
+// The units and files below fix up systemd units of SuSE CHost, see docs/systemd-units.md. They are written by the
+// provision script and returned as extension units and files on reconciliation, so that gardener-node-agent keeps them
+// converged on running nodes.
+const (
+	containerdUnitName             = v1beta1constants.OperatingSystemConfigUnitNameContainerDService
+	containerdExecConfigDropInName = "11-exec_config.conf"
+
+	// containerdDockerFixUnitName is the unit removing the conflict of containerd with docker and disabling docker.
+	containerdDockerFixUnitName   = "containerd-docker-fix.service"
+	containerdDockerFixScriptPath = "/opt/bin/containerd-docker-fix.sh"
+
+	journaldUnitName                    = "systemd-journald.service"
+	journaldPersistentStorageConfigPath = "/etc/systemd/journald.conf.d/10-use-persistent-log-storage.conf"
+)
+
+const (
+	containerdExecConfigDropIn = `[Service]
+ExecStart=
+ExecStart=/usr/sbin/containerd --config=` + containerdConfigPath + `
+`
+
+	containerdDockerFixUnit = `[Unit]
+Description=Remove the conflict of containerd with docker and disable docker
+Documentation=https://github.com/gardener/gardener-extension-os-suse-chost/tree/master/docs/systemd-units.md
+Before=` + containerdUnitName + `
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=` + containerdDockerFixScriptPath + `
+
+[Install]
+WantedBy=multi-user.target
+`
+
+	// containerdDockerFixScript derives the containerd unit without the conflict from the vendor unit every time it
+	// runs, so that the fixed unit follows updates of the vendor unit. It removes the fixed unit again once the vendor
+	// unit no longer conflicts with docker.
+	containerdDockerFixScript = `#!/bin/bash
+# refer to https://github.com/gardener/gardener-extension-os-suse-chost/tree/master/docs/systemd-units.md
+set -o errexit
+
+VENDOR_UNIT=/usr/lib/systemd/system/` + containerdUnitName + `
+UNIT=/etc/systemd/system/` + containerdUnitName + `
+MARKER="# managed by gardener-extension-os-suse-chost"
+
+if [[ -f "${VENDOR_UNIT}" ]] && grep -qE '^Conflicts=.*docker' "${VENDOR_UNIT}"; then
+  { echo "${MARKER}"; sed -re 's/Conflicts=(.*)(docker.service|docker)(.*)/Conflicts=\1 \3/g' "${VENDOR_UNIT}"; } > "${UNIT}.tmp"
+  if cmp -s "${UNIT}.tmp" "${UNIT}"; then
+    rm -f "${UNIT}.tmp"
+  else
+    mv "${UNIT}.tmp" "${UNIT}"
+    systemctl daemon-reload
+  fi
+elif [[ -f "${UNIT}" ]] && [[ "$(head -n 1 "${UNIT}")" == "${MARKER}" ]]; then
+  rm -f "${UNIT}"
+  systemctl daemon-reload
+fi
+
+if systemctl cat docker.service >/dev/null 2>&1; then
+  systemctl disable --now docker.service
+fi
+`
+
+	journaldPersistentStorageConfig = `[Journal]
+Storage=persistent
+`
+)
+
+// containerdFixupUnits returns the units fixing up the containerd unit of SuSE CHost.
+func containerdFixupUnits() []extensionsv1alpha1.Unit {
+	return []extensionsv1alpha1.Unit{{
+		Name:    containerdUnitName,
+		DropIns: []extensionsv1alpha1.DropIn{{Name: containerdExecConfigDropInName, Content: containerdExecConfigDropIn}},
+	}}
+}
+
+// dockerFixupUnits returns the units removing the conflict of containerd with docker.
+func dockerFixupUnits() []extensionsv1alpha1.Unit {
+	return []extensionsv1alpha1.Unit{{
+		Name:      containerdDockerFixUnitName,
+		Enable:    ptr.To(true),
+		Content:   ptr.To(containerdDockerFixUnit),
+		FilePaths: []string{containerdDockerFixScriptPath},
+	}}
+}
+
+// dockerFixupFiles returns the files required by the units returned by dockerFixupUnits.
+func dockerFixupFiles() []extensionsv1alpha1.File {
+	return []extensionsv1alpha1.File{inlineFile(containerdDockerFixScriptPath, 0755, containerdDockerFixScript)}
+}
+
+// journaldFixupUnits returns the units setting the journald storage to persistent, such that logs are written to
+// /var/log instead of /run/log.
+func journaldFixupUnits() []extensionsv1alpha1.Unit {
+	return []extensionsv1alpha1.Unit{{
+		Name:      journaldUnitName,
+		FilePaths: []string{journaldPersistentStorageConfigPath},
+	}}
+}
+
+// journaldFixupFiles returns the files required by the units returned by journaldFixupUnits.
+func journaldFixupFiles() []extensionsv1alpha1.File {
+	return []extensionsv1alpha1.File{inlineFile(journaldPersistentStorageConfigPath, 0644, journaldPersistentStorageConfig)}
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Units", func() {
+	Describe("units fragment", func() {
+		unitCommandsScript := func(units []extensionsv1alpha1.Unit) string {
+			GinkgoHelper()
+			script, err := renderProvisionScriptFragment(provisionScriptFragmentUnits, &provisionScriptData{Units: units})
+			Expect(err).NotTo(HaveOccurred())
+			return script
+		}
+
+		DescribeTable("should apply the unit",
+			func(unit extensionsv1alpha1.Unit, expected string) {
+				unit.Name = "foo.service"
+				Expect(unitCommandsScript([]extensionsv1alpha1.Unit{unit})).To(Equal(expected))
+			},
+			Entry("content",
+				extensionsv1alpha1.Unit{Content: ptr.To("[Unit]")},
+				"systemctl enable 'foo.service' && systemctl restart --no-block 'foo.service'\n",
+			),
+			Entry("content, enabled",
+				extensionsv1alpha1.Unit{Content: ptr.To("[Unit]"), Enable: ptr.To(true)},
+				"systemctl enable 'foo.service' && systemctl restart --no-block 'foo.service'\n",
+			),
+			Entry("content, disabled",
+				extensionsv1alpha1.Unit{Content: ptr.To("[Unit]"), Enable: ptr.To(false)},
+				"systemctl disable 'foo.service'\nsystemctl stop --no-block 'foo.service'\n",
+			),
+			Entry("content, restart command",
+				extensionsv1alpha1.Unit{Content: ptr.To("[Unit]"), Command: ptr.To(extensionsv1alpha1.CommandRestart)},
+				"systemctl enable 'foo.service' && systemctl restart --no-block 'foo.service'\n",
+			),
+			Entry("content, stop command",
+				extensionsv1alpha1.Unit{Content: ptr.To("[Unit]"), Command: ptr.To(extensionsv1alpha1.CommandStop)},
+				"systemctl enable 'foo.service'\nsystemctl stop --no-block 'foo.service'\n",
+			),
+			Entry("content, enabled, stop command",
+				extensionsv1alpha1.Unit{Content: ptr.To("[Unit]"), Enable: ptr.To(true), Command: ptr.To(extensionsv1alpha1.CommandStop)},
+				"systemctl enable 'foo.service'\nsystemctl stop --no-block 'foo.service'\n",
+			),
+			Entry("content, disabled, restart command",
+				extensionsv1alpha1.Unit{Content: ptr.To("[Unit]"), Enable: ptr.To(false), Command: ptr.To(extensionsv1alpha1.CommandRestart)},
+				"systemctl disable 'foo.service'\nsystemctl stop --no-block 'foo.service'\n",
+			),
+			Entry("content, disabled, stop command",
+				extensionsv1alpha1.Unit{Content: ptr.To("[Unit]"), Enable: ptr.To(false), Command: ptr.To(extensionsv1alpha1.CommandStop)},
+				"systemctl disable 'foo.service'\nsystemctl stop --no-block 'foo.service'\n",
+			),
+			Entry("drop-ins only",
+				extensionsv1alpha1.Unit{DropIns: []extensionsv1alpha1.DropIn{{Name: "10-foo.conf", Content: "[Service]"}}},
+				"systemctl enable 'foo.service' && systemctl try-restart --no-block 'foo.service'\n",
+			),
+			Entry("drop-ins only, enabled",
+				extensionsv1alpha1.Unit{DropIns: []extensionsv1alpha1.DropIn{{Name: "10-foo.conf", Content: "[Service]"}}, Enable: ptr.To(true)},
+				"systemctl enable 'foo.service' && systemctl try-restart --no-block 'foo.service'\n",
+			),
+			Entry("drop-ins only, disabled",
+				extensionsv1alpha1.Unit{DropIns: []extensionsv1alpha1.DropIn{{Name: "10-foo.conf", Content: "[Service]"}}, Enable: ptr.To(false)},
+				"systemctl disable 'foo.service'\nsystemctl stop --no-block 'foo.service'\n",
+			),
+			Entry("drop-ins only, restart command",
+				extensionsv1alpha1.Unit{DropIns: []extensionsv1alpha1.DropIn{{Name: "10-foo.conf", Content: "[Service]"}}, Command: ptr.To(extensionsv1alpha1.CommandRestart)},
+				"systemctl enable 'foo.service' && systemctl try-restart --no-block 'foo.service'\n",
+			),
+			Entry("drop-ins only, stop command",
+				extensionsv1alpha1.Unit{DropIns: []extensionsv1alpha1.DropIn{{Name: "10-foo.conf", Content: "[Service]"}}, Command: ptr.To(extensionsv1alpha1.CommandStop)},
+				"systemctl enable 'foo.service'\nsystemctl stop --no-block 'foo.service'\n",
+			),
+		)
+
+		It("should queue the jobs of the units in their declared order", func() {
+			Expect(unitCommandsScript([]extensionsv1alpha1.Unit{
+				{Name: "c.service", Content: ptr.To("[Unit]")},
+				{Name: "a.service", Content: ptr.To("[Unit]"), Enable: ptr.To(false)},
+				{Name: "b.service", DropIns: []extensionsv1alpha1.DropIn{{Name: "10-b.conf", Content: "[Service]"}}},
+			})).To(Equal(`systemctl enable 'c.service' && systemctl restart --no-block 'c.service'
+systemctl disable 'a.service'
+systemctl stop --no-block 'a.service'
+systemctl enable 'b.service' && systemctl try-restart --no-block 'b.service'
+`))
+		})
+
+		It("should be empty if there are no units", func() {
+			Expect(unitCommandsScript(nil)).To(BeEmpty())
+		})
+	})
+})
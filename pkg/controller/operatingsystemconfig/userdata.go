@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gardener/gardener/extensions/pkg/controller/operatingsystemconfig"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/config"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/susechost"
+)
+
+// largestFilesInError is the number of files named in the error for user data exceeding the maximum size.
+const largestFilesInError = 3
+
+// userDataSize is the size of the user data returned for OperatingSystemConfigs with purpose provision.
+var userDataSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "suse_chost_user_data_size_bytes",
+	Help: "Size of the user data of OperatingSystemConfigs with purpose provision in bytes, after compression.",
+}, []string{"namespace", "name"})
+
+func init() {
+	metrics.Registry.MustRegister(userDataSize)
+}
+
+// limitUserDataSize returns the user data for the given OperatingSystemConfig if it does not exceed the maximum size in
+// the controller configuration. Otherwise, it is compressed if configured and supported by the operating system. If it
+// still exceeds the maximum size, an error naming the largest files of the OperatingSystemConfig is returned.
+func (a *actuator) limitUserDataSize(ctx context.Context, osc *extensionsv1alpha1.OperatingSystemConfig, userData []byte) ([]byte, error) {
+	if a.config.UserData == nil || a.config.UserData.MaxSize == nil {
+		return userData, nil
+	}
+
+	maxSize := a.config.UserData.MaxSize.Value()
+	if int64(len(userData)) <= maxSize {
+		return userData, nil
+	}
+
+	size := fmt.Sprintf("%d bytes", len(userData))
+	// The MemoryOne hypervisor reads the vSMP configuration from the user data, it cannot be compressed.
+	if a.config.UserData.Compression != nil && osc.Spec.Type == susechost.OSTypeSuSECHost {
+		compressed, err := compressUserData(userData, *a.config.UserData.Compression)
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(compressed)) <= maxSize {
+			return compressed, nil
+		}
+		size += fmt.Sprintf(" (%d bytes compressed)", len(compressed))
+	}
+
+	largestFiles, err := a.largestFiles(ctx, osc)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fmt.Errorf("user data of %s exceeds the maximum size of %d bytes", size, maxSize)
+	if len(largestFiles) > 0 {
+		err = fmt.Errorf("%w, largest files: %s", err, strings.Join(largestFiles, ", "))
+	}
+	return nil, err
+}
+
+// compressUserData compresses the given user data. The result must be stable, see handleProvisionOSC.
+func compressUserData(userData []byte, compression config.UserDataCompression) ([]byte, error) {
+	if compression != config.UserDataCompressionGzip {
+		return nil, fmt.Errorf("unsupported user data compression %q", compression)
+	}
+
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(userData); err != nil {
+		return nil, fmt.Errorf("failed compressing user data: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed compressing user data: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// largestFiles returns the paths of the files of the given OperatingSystemConfig which take the most space in the
+// provision script, together with their size.
+func (a *actuator) largestFiles(ctx context.Context, osc *extensionsv1alpha1.OperatingSystemConfig) ([]string, error) {
+	type fileSize struct {
+		path string
+		size int
+	}
+
+	sizes := make([]fileSize, 0, len(osc.Spec.Files))
+	for _, file := range osc.Spec.Files {
+		script, err := operatingsystemconfig.FilesToDiskScript(ctx, a.client, osc.Namespace, []extensionsv1alpha1.File{file})
+		if err != nil {
+			return nil, err
+		}
+		sizes = append(sizes, fileSize{file.Path, len(script)})
+	}
+
+	slices.SortStableFunc(sizes, func(a, b fileSize) int { return cmp.Compare(b.size, a.size) })
+
+	var largestFiles []string
+	for _, file := range sizes[:min(len(sizes), largestFilesInError)] {
+		largestFiles = append(largestFiles, fmt.Sprintf("%s (%d bytes)", file.path, file.size))
+	}
+	return largestFiles, nil
+}
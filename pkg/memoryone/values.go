@@ -10,27 +10,46 @@ import (
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
-	runtimeutils "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
-	memoryonechost "github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/v1alpha1"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/install"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/memoryonechost/validation"
 )
 
 var decoder runtime.Decoder
 
 func init() {
 	scheme := runtime.NewScheme()
-	runtimeutils.Must(memoryonechost.AddToScheme(scheme))
-	decoder = serializer.NewCodecFactory(scheme).UniversalDecoder()
+	install.Install(scheme)
+	decoder = serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDecoder()
 }
 
+// Configuration decodes and validates the memoryone-chost provider configuration of the given OperatingSystemConfig.
+// It returns nil if the OperatingSystemConfig does not carry a provider configuration.
 func Configuration(osc *extensionsv1alpha1.OperatingSystemConfig) (*memoryonechost.OperatingSystemConfiguration, error) {
 	if osc.Spec.ProviderConfig == nil {
 		return nil, nil
 	}
 
+	obj, err := DecodeConfiguration(osc.Spec.ProviderConfig.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := validation.ValidateOperatingSystemConfiguration(obj, field.NewPath("spec", "providerConfig")); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid provider config: %w", errs.ToAggregate())
+	}
+
+	return obj, nil
+}
+
+// DecodeConfiguration strictly decodes the given raw memoryone-chost provider configuration, i.e. unknown or
+// duplicate fields lead to an error.
+func DecodeConfiguration(raw []byte) (*memoryonechost.OperatingSystemConfiguration, error) {
 	obj := &memoryonechost.OperatingSystemConfiguration{}
-	if _, _, err := decoder.Decode(osc.Spec.ProviderConfig.Raw, nil, obj); err != nil {
-		return nil, fmt.Errorf("failed to decode provider config: %+v", err)
+	if _, _, err := decoder.Decode(raw, nil, obj); err != nil {
+		return nil, fmt.Errorf("failed to decode provider config: %w", err)
 	}
 
 	return obj, nil
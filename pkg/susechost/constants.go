@@ -4,10 +4,26 @@
 
 package susechost
 
+import (
+	"github.com/Masterminds/semver/v3"
+)
+
 const (
 	// ControllerName is the name of the controller.
 	ControllerName = "suse-chost"
 
 	// OSTypeSuSECHost is a constant for the suse-chost extension OS type.
 	OSTypeSuSECHost = "suse-chost"
+
+	// AnnotationUnifiedCgroupHierarchy is the Shoot annotation which makes suse-chost nodes boot with the unified cgroup
+	// hierarchy (cgroup v2) if set to "true" and the worker pool does not configure `unifiedCgroupHierarchy` in its
+	// provider configuration.
+	// Deprecated: Use the `unifiedCgroupHierarchy` field of the provider configuration instead.
+	AnnotationUnifiedCgroupHierarchy = "suse-chost.os.extensions.gardener.cloud/unified-cgroup-hierarchy"
 )
+
+// KubeletCgroupV1RemovedVersion is the earliest Kubernetes version in which cgroup v1 support (and therefore the
+// --fail-cgroupv1 flag) may be removed from kubelet. KEP-5573 states: "The removal will be done no earlier than 1.38 to
+// maintain the k8s deprecation policy."
+// See https://github.com/kubernetes/enhancements/tree/master/keps/sig-node/5573-remove-cgroup-v1
+var KubeletCgroupV1RemovedVersion = semver.MustParse("1.38.0")
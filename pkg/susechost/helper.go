@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package susechost
+
+import (
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost"
+)
+
+// UnifiedCgroupHierarchyEnabled returns true if suse-chost nodes with the given provider configuration boot with the
+// unified cgroup hierarchy (cgroup v2). The `unifiedCgroupHierarchy` field of the configuration takes precedence, the
+// deprecated AnnotationUnifiedCgroupHierarchy of the Shoot is only considered if the field is unset.
+func UnifiedCgroupHierarchyEnabled(config *susechost.OperatingSystemConfiguration, shootAnnotations map[string]string) bool {
+	if config != nil && config.UnifiedCgroupHierarchy != nil {
+		return *config.UnifiedCgroupHierarchy
+	}
+
+	return shootAnnotations[AnnotationUnifiedCgroupHierarchy] == "true"
+}
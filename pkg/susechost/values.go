@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package susechost
+
+import (
+	"fmt"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost/install"
+	"github.com/gardener/gardener-extension-os-suse-chost/pkg/apis/susechost/validation"
+)
+
+var decoder runtime.Decoder
+
+func init() {
+	scheme := runtime.NewScheme()
+	install.Install(scheme)
+	decoder = serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDecoder()
+}
+
+// Configuration decodes and validates the suse-chost provider configuration of the given OperatingSystemConfig.
+// It returns an empty configuration if the OperatingSystemConfig does not carry a provider configuration.
+func Configuration(osc *extensionsv1alpha1.OperatingSystemConfig) (*susechost.OperatingSystemConfiguration, error) {
+	if osc.Spec.ProviderConfig == nil {
+		return &susechost.OperatingSystemConfiguration{}, nil
+	}
+
+	obj, err := DecodeConfiguration(osc.Spec.ProviderConfig.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := validation.ValidateOperatingSystemConfiguration(obj, field.NewPath("spec", "providerConfig")); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid provider config: %w", errs.ToAggregate())
+	}
+
+	return obj, nil
+}
+
+// DecodeConfiguration strictly decodes the given raw suse-chost provider configuration, i.e. unknown or duplicate
+// fields lead to an error.
+func DecodeConfiguration(raw []byte) (*susechost.OperatingSystemConfiguration, error) {
+	obj := &susechost.OperatingSystemConfiguration{}
+	if _, _, err := decoder.Decode(raw, nil, obj); err != nil {
+		return nil, fmt.Errorf("failed to decode provider config: %w", err)
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package script contains helpers for testing the bash scripts generated by the extension.
+package script
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// Check parses the given bash script and returns an error listing all problems found in it:
+//   - syntax errors, including unclosed quotes, blocks and here-documents,
+//   - parameter expansions and command substitutions which are subject to word splitting, i.e., which are not quoted,
+//   - here-documents with an unquoted delimiter whose body contains expansions.
+//
+// Expansions of special parameters which cannot be split, e.g. `$?`, and arithmetic expansions are allowed unquoted.
+func Check(script string) error {
+	file, err := syntax.NewParser(syntax.Variant(syntax.LangBash)).Parse(strings.NewReader(script), "")
+	if err != nil {
+		return fmt.Errorf("failed to parse script: %w", err)
+	}
+
+	var errs []error
+	syntax.Walk(file, func(node syntax.Node) bool {
+		switch n := node.(type) {
+		case *syntax.CallExpr:
+			for _, word := range n.Args {
+				errs = append(errs, checkWordQuoted(word)...)
+			}
+
+		case *syntax.ForClause:
+			if iter, ok := n.Loop.(*syntax.WordIter); ok {
+				for _, word := range iter.Items {
+					errs = append(errs, checkWordQuoted(word)...)
+				}
+			}
+
+		case *syntax.Redirect:
+			if n.Op != syntax.Hdoc && n.Op != syntax.DashHdoc {
+				errs = append(errs, checkWordQuoted(n.Word)...)
+			} else if n.Hdoc != nil && !isQuotedDelimiter(n.Word) {
+				for _, part := range n.Hdoc.Parts {
+					if isExpansion(part) {
+						errs = append(errs, fmt.Errorf("%s: here-document with unquoted delimiter %q expands %s", part.Pos(), n.Word.Lit(), printNode(part)))
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	return errors.Join(errs...)
+}
+
+func checkWordQuoted(word *syntax.Word) []error {
+	if word == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, part := range word.Parts {
+		if isExpansion(part) {
+			errs = append(errs, fmt.Errorf("%s: unquoted expansion %s", part.Pos(), printNode(part)))
+		}
+	}
+	return errs
+}
+
+// isExpansion returns true if the given word part is a parameter expansion or command substitution whose result may
+// contain whitespace.
+func isExpansion(part syntax.WordPart) bool {
+	switch p := part.(type) {
+	case *syntax.ParamExp:
+		if p.Length {
+			return false
+		}
+		switch p.Param.Value {
+		case "?", "#", "$", "!":
+			return false
+		}
+		return true
+	case *syntax.CmdSubst:
+		return true
+	default:
+		return false
+	}
+}
+
+// isQuotedDelimiter returns true if the given here-document delimiter is quoted, in which case the body of the
+// here-document is not expanded.
+func isQuotedDelimiter(word *syntax.Word) bool {
+	for _, part := range word.Parts {
+		switch p := part.(type) {
+		case *syntax.SglQuoted, *syntax.DblQuoted:
+			return true
+		case *syntax.Lit:
+			if strings.Contains(p.Value, `\`) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func printNode(node syntax.Node) string {
+	var out strings.Builder
+	if err := syntax.NewPrinter().Print(&out, node); err != nil {
+		return fmt.Sprintf("<%T>", node)
+	}
+	return out.String()
+}
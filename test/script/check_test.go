@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package script_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+
+	. "github.com/gardener/gardener-extension-os-suse-chost/test/script"
+)
+
+var _ = Describe("Check", func() {
+	DescribeTable("should accept valid scripts",
+		func(script string) {
+			Expect(Check(script)).To(Succeed())
+		},
+		Entry("empty script", ""),
+		Entry("quoted expansions", `echo "${FOO}" "$(date)" "foo ${BAR:-bar}"`+"\n"),
+		Entry("assignments", `FOO=$(date)
+BAR=${FOO}
+`),
+		Entry("tests", `if [[ -f ${FOO} && $(cat "${FOO}") == "" ]]; then echo; fi`+"\n"),
+		Entry("special parameters and arithmetic", `exit $?; echo $# ${#FOO} $(( FOO + 1 ))`+"\n"),
+		Entry("here-document without expansions", "cat << EOF | base64 -d > \"/foo\"\nZm9v\nEOF\n"),
+		Entry("here-document with quoted delimiter", "cat << 'EOF' > \"/foo\"\n${FOO}\nEOF\n"),
+		Entry("here-document with escaped delimiter", "cat << \\EOF > \"/foo\"\n$(date)\nEOF\n"),
+	)
+
+	DescribeTable("should reject invalid scripts",
+		func(script string, matcher types.GomegaMatcher) {
+			Expect(Check(script)).To(MatchError(matcher))
+		},
+		Entry("syntax error", "if true; then\necho\n", ContainSubstring(`if statement must end with "fi"`)),
+		Entry("unclosed quote", "echo 'foo\n", ContainSubstring("reached EOF without closing quote")),
+		Entry("unclosed here-document", "cat << EOF > /foo\nfoo\n", ContainSubstring("unclosed here-document 'EOF'")),
+		Entry("here-document with indented delimiter", "cat << EOF > /foo\nfoo\n  EOF\n", ContainSubstring("unclosed here-document 'EOF'")),
+		Entry("unquoted parameter expansion", "rm -rf ${FOO}/bar\n", Equal("1:8: unquoted expansion ${FOO}")),
+		Entry("unquoted command substitution", "echo $(date)\n", Equal("1:6: unquoted expansion $(date)")),
+		Entry("unquoted expansion in command substitution", `FOO="$(cat $BAR)"`+"\n", Equal("1:12: unquoted expansion $BAR")),
+		Entry("unquoted expansion in redirect", "echo > ${FOO}\n", Equal("1:8: unquoted expansion ${FOO}")),
+		Entry("unquoted expansion in for loop", "for f in ${FOO}; do echo \"${f}\"; done\n", Equal("1:10: unquoted expansion ${FOO}")),
+		Entry("here-document with expansions", "cat << EOF > /foo\n${FOO}\nEOF\n", Equal(`2:1: here-document with unquoted delimiter "EOF" expands ${FOO}`)),
+		Entry("multiple problems", "echo ${FOO} $(date)\n", And(
+			ContainSubstring("1:6: unquoted expansion ${FOO}"),
+			ContainSubstring("1:13: unquoted expansion $(date)"),
+		)),
+	)
+})
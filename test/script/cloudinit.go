@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package script
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// cloudConfig is the part of the cloud-config format understood by CloudInitScript.
+type cloudConfig struct {
+	WriteFiles []struct {
+		Path        string `yaml:"path"`
+		Permissions string `yaml:"permissions"`
+		Encoding    string `yaml:"encoding"`
+		Content     string `yaml:"content"`
+	} `yaml:"write_files"`
+	RunCmd []any `yaml:"runcmd"`
+}
+
+// CloudInitScript returns a bash script applying the given cloud-config like cloud-init does: it writes the
+// `write_files` and runs the `runcmd` entries. Entries running `/bin/bash -c` are run in a subshell instead, so that
+// the paths of their scripts are rewritten by the Sandbox as well.
+func CloudInitScript(userData string) (string, error) {
+	if !strings.HasPrefix(userData, "#cloud-config\n") {
+		return "", errors.New("user data is not a cloud-config")
+	}
+
+	config := &cloudConfig{}
+	if err := yaml.Unmarshal([]byte(userData), config); err != nil {
+		return "", fmt.Errorf("failed to parse cloud-config: %w", err)
+	}
+
+	var out strings.Builder
+	out.WriteString("#!/bin/bash\n")
+
+	for _, file := range config.WriteFiles {
+		content := file.Content
+		if file.Encoding != "b64" {
+			content = base64.StdEncoding.EncodeToString([]byte(content))
+		}
+
+		fmt.Fprintf(&out, "mkdir -p %q\ncat << EOF | base64 -d > %q\n%s\nEOF\n", path.Dir(file.Path), file.Path, content)
+		if file.Permissions != "" {
+			fmt.Fprintf(&out, "chmod %q %q\n", file.Permissions, file.Path)
+		}
+	}
+
+	for _, command := range config.RunCmd {
+		switch command := command.(type) {
+		case string:
+			out.WriteString(command + "\n")
+		case []any:
+			args := make([]string, 0, len(command))
+			for _, arg := range command {
+				args = append(args, fmt.Sprint(arg))
+			}
+
+			if len(args) == 3 && args[0] == "/bin/bash" && args[1] == "-c" {
+				out.WriteString("(\n" + args[2] + ")\n")
+				continue
+			}
+
+			for i, arg := range args {
+				// Paths are double-quoted, so that the Sandbox rewrites them.
+				if strings.HasPrefix(arg, "/") {
+					args[i] = fmt.Sprintf("%q", arg)
+					continue
+				}
+
+				quoted, err := syntax.Quote(arg, syntax.LangBash)
+				if err != nil {
+					return "", err
+				}
+				args[i] = quoted
+			}
+			out.WriteString(strings.Join(args, " ") + "\n")
+		default:
+			return "", fmt.Errorf("unsupported runcmd entry %v", command)
+		}
+	}
+
+	return out.String(), nil
+}
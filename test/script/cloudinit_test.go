@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package script_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gardener/gardener-extension-os-suse-chost/test/script"
+)
+
+var _ = Describe("CloudInit", func() {
+	Describe("#CloudInitScript", func() {
+		It("should write the files and run the commands", func() {
+			sandbox, err := NewSandbox(GinkgoT().TempDir(), nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			script, err := CloudInitScript(`#cloud-config
+write_files:
+- path: /etc/foo/bar
+  permissions: "0600"
+  content: |
+    bar
+- path: /etc/foo/baz
+  encoding: b64
+  content: YmF6
+runcmd:
+- set -e
+- [systemctl, enable, foo bar]
+- - /bin/bash
+  - -c
+  - |
+    cat /etc/foo/baz > /etc/foo/qux
+- [touch, /etc/foo/applied]
+`)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sandbox.Run(context.Background(), script)).To(BeEmpty())
+			Expect(sandbox.ReadFile("/etc/foo/bar")).To(Equal("bar\n"))
+			Expect(sandbox.ReadFile("/etc/foo/qux")).To(Equal("baz"))
+			Expect(sandbox.ReadFile("/etc/foo/applied")).To(BeEmpty())
+			Expect(sandbox.Commands()).To(Equal([]string{"systemctl enable foo bar"}))
+		})
+
+		It("should fail for other user data", func() {
+			Expect(CloudInitScript("#!/bin/bash\n")).Error().To(MatchError("user data is not a cloud-config"))
+		})
+	})
+})
@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package script
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// DefaultShims are the commands stubbed in a Sandbox by default, because they would modify the host or are not
+// available outside of SuSE CHost. The values are the bash snippets run by the shims after recording the invocation.
+var DefaultShims = map[string]string{
+	"systemctl":      "",
+	"zypper":         "",
+	"rpm":            "",
+	"containerd":     `[[ "$1" == "--version" ]] && echo "containerd github.com/containerd/containerd v1.7.20 8fc6bcff51318944179630522a095cc9dbf9f353"`,
+	"hostname":       "echo node-1",
+	"ln":             "",
+	"grub2-mkconfig": "",
+}
+
+// Sandbox runs bash scripts against a fake root directory. All absolute paths in a script are rewritten to paths
+// below the fake root, except for `/dev/null`. Commands which would modify the host are replaced by shims recording
+// their invocations.
+type Sandbox struct {
+	// Root is the fake root directory.
+	Root string
+
+	shimsDir string
+	logFile  string
+}
+
+// baseDirs are the directories every image has, scripts rely on them to exist.
+var baseDirs = []string{"/dev", "/etc/systemd/system"}
+
+// NewSandbox creates a Sandbox in the given directory. The fake root contains the empty base directories of an image.
+// The given shims are added to or override the DefaultShims.
+func NewSandbox(dir string, shims map[string]string) (*Sandbox, error) {
+	s := &Sandbox{
+		Root:     filepath.Join(dir, "root"),
+		shimsDir: filepath.Join(dir, "shims"),
+		logFile:  filepath.Join(dir, "commands.log"),
+	}
+
+	dirs := []string{s.shimsDir}
+	for _, d := range baseDirs {
+		dirs = append(dirs, s.Path(d))
+	}
+	for _, d := range dirs {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	allShims := maps.Clone(DefaultShims)
+	maps.Copy(allShims, shims)
+	for name, body := range allShims {
+		shim := `#!/bin/bash
+printf '%s' "$(basename "$0")" >> "${SANDBOX_COMMANDS_LOG}"
+for arg in "$@"; do printf ' %s' "${arg}" >> "${SANDBOX_COMMANDS_LOG}"; done
+echo >> "${SANDBOX_COMMANDS_LOG}"
+` + body + "\n"
+		if err := os.WriteFile(filepath.Join(s.shimsDir, name), []byte(shim), 0755); err != nil { // #nosec: G306 -- The shims must be executable.
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Run runs the given script in the sandbox and returns its combined output.
+func (s *Sandbox) Run(ctx context.Context, script string) ([]byte, error) {
+	rewritten, err := RewritePaths(script, s.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	scriptFile := filepath.Join(filepath.Dir(s.shimsDir), "script.sh")
+	if err := os.WriteFile(scriptFile, []byte(rewritten), 0600); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, "bash", scriptFile) // #nosec: G204 -- The script is run in a sandbox in tests only.
+	cmd.Dir = s.Root
+	cmd.Env = []string{
+		"PATH=" + s.shimsDir + string(os.PathListSeparator) + os.Getenv("PATH"),
+		"HOME=" + s.Root,
+		"SANDBOX_COMMANDS_LOG=" + s.logFile,
+	}
+	return cmd.CombinedOutput()
+}
+
+// Commands returns the invocations of the shims recorded so far, one per line, in the order they happened. Paths in
+// the fake root are returned as absolute paths, i.e., without the path of the fake root.
+func (s *Sandbox) Commands() ([]string, error) {
+	data, err := os.ReadFile(s.logFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(string(data), s.Root, ""), "\n"), "\n"), nil
+}
+
+// Path returns the path of the given absolute path in the fake root.
+func (s *Sandbox) Path(path string) string {
+	return filepath.Join(s.Root, path)
+}
+
+// ReadFile reads the file at the given absolute path in the fake root.
+func (s *Sandbox) ReadFile(path string) (string, error) {
+	data, err := os.ReadFile(s.Path(path))
+	return string(data), err
+}
+
+// RewritePaths rewrites all words of the given bash script which start with an absolute path to start with the given
+// root instead, except for `/dev/null`. The bodies of here-documents are not rewritten.
+func RewritePaths(script, root string) (string, error) {
+	file, err := syntax.NewParser(syntax.Variant(syntax.LangBash)).Parse(strings.NewReader(script), "")
+	if err != nil {
+		return "", fmt.Errorf("failed to parse script: %w", err)
+	}
+
+	syntax.Walk(file, func(node syntax.Node) bool {
+		switch n := node.(type) {
+		case *syntax.Redirect:
+			// Walk the redirect without its here-document body.
+			if n.Word != nil {
+				rewriteWord(n.Word, root)
+			}
+			return false
+		case *syntax.Word:
+			rewriteWord(n, root)
+		}
+		return true
+	})
+
+	var out strings.Builder
+	if err := syntax.NewPrinter().Print(&out, file); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func rewriteWord(word *syntax.Word, root string) {
+	if len(word.Parts) == 0 {
+		return
+	}
+
+	part := word.Parts[0]
+	if quoted, ok := part.(*syntax.DblQuoted); ok && len(quoted.Parts) > 0 {
+		part = quoted.Parts[0]
+	}
+
+	switch p := part.(type) {
+	case *syntax.Lit:
+		if isRewrittenPath(p.Value) {
+			p.Value = root + p.Value
+		}
+	case *syntax.SglQuoted:
+		if isRewrittenPath(p.Value) {
+			p.Value = root + p.Value
+		}
+	}
+}
+
+func isRewrittenPath(value string) bool {
+	return strings.HasPrefix(value, "/") && value != "/dev/null"
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package script_test
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gardener/gardener-extension-os-suse-chost/test/script"
+)
+
+var _ = Describe("Sandbox", func() {
+	DescribeTable("#RewritePaths",
+		func(script, expected string) {
+			Expect(RewritePaths(script, "/root")).To(Equal(expected))
+		},
+		Entry("absolute paths", "mkdir -p /etc/foo\n", "mkdir -p /root/etc/foo\n"),
+		Entry("quoted absolute paths", `cat "/etc/foo" > "/etc/bar"`+"\n", `cat "/root/etc/foo" >"/root/etc/bar"`+"\n"),
+		Entry("single-quoted absolute paths", "FOO='/etc/foo'\n", "FOO='/root/etc/foo'\n"),
+		Entry("relative paths and expansions", `ln -s foo "${FOO}/bar"`+"\n", `ln -s foo "${FOO}/bar"`+"\n"),
+		Entry("/dev/null", "echo > /dev/null\n", "echo >/dev/null\n"),
+		Entry("assignments and tests", "FOO=/etc/foo\nif [[ -f /etc/foo ]]; then echo; fi\n", "FOO=/root/etc/foo\nif [[ -f /root/etc/foo ]]; then echo; fi\n"),
+		Entry("here-document bodies", "cat << EOF > /etc/foo\n/etc/bar\nEOF\n", "cat <<EOF >/root/etc/foo\n/etc/bar\nEOF\n"),
+	)
+
+	It("should fail to rewrite invalid scripts", func() {
+		Expect(RewritePaths("echo 'foo\n", "/root")).Error().To(MatchError(ContainSubstring("failed to parse script")))
+	})
+
+	Describe("#Run", func() {
+		var (
+			ctx     = context.Background()
+			sandbox *Sandbox
+		)
+
+		BeforeEach(func() {
+			var err error
+			sandbox, err = NewSandbox(GinkgoT().TempDir(), map[string]string{"foo": "echo foo output"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should write files to the fake root", func() {
+			Expect(sandbox.Run(ctx, "mkdir -p /etc/foo\necho bar > /etc/foo/bar\n")).To(BeEmpty())
+			Expect(sandbox.ReadFile("/etc/foo/bar")).To(Equal("bar\n"))
+			Expect(sandbox.Commands()).To(BeEmpty())
+		})
+
+		It("should record the invocations of the shims", func() {
+			Expect(sandbox.Run(ctx, "foo\nsystemctl enable 'foo bar' && systemctl restart /etc/foo\nhostname > /etc/hostname\n")).To(Equal([]byte("foo output\n")))
+			Expect(sandbox.Commands()).To(Equal([]string{"foo", "systemctl enable foo bar", "systemctl restart /etc/foo", "hostname"}))
+			Expect(sandbox.ReadFile("/etc/hostname")).To(Equal("node-1\n"))
+		})
+
+		It("should return the exit code of the script", func() {
+			output, err := sandbox.Run(ctx, "echo failed\nexit 3\n")
+			Expect(err).To(MatchError("exit status 3"))
+			Expect(string(output)).To(Equal("failed\n"))
+		})
+
+		It("should not touch files outside of the fake root", func() {
+			dir := GinkgoT().TempDir()
+			file := dir + "/foo"
+			Expect(sandbox.Run(ctx, "mkdir -p "+dir+"\necho foo > "+file+"\n")).To(BeEmpty())
+			Expect(file).NotTo(BeAnExistingFile())
+			Expect(os.ReadFile(sandbox.Path(file))).To(Equal([]byte("foo\n")))
+		})
+	})
+})